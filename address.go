@@ -29,27 +29,27 @@ var g = &grammar{
 	rules: []*rule{
 		{
 			name: "Address",
-			pos:  position{line: 31, col: 1, offset: 362},
+			pos:  position{line: 31, col: 1, offset: 378},
 			expr: &actionExpr{
-				pos: position{line: 31, col: 11, offset: 372},
+				pos: position{line: 31, col: 11, offset: 388},
 				run: (*parser).callonAddress1,
 				expr: &seqExpr{
-					pos: position{line: 31, col: 11, offset: 372},
+					pos: position{line: 31, col: 11, offset: 388},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 31, col: 11, offset: 372},
+							pos:   position{line: 31, col: 11, offset: 388},
 							label: "m",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 31, col: 13, offset: 374},
+								pos: position{line: 31, col: 13, offset: 390},
 								expr: &seqExpr{
-									pos: position{line: 31, col: 14, offset: 375},
+									pos: position{line: 31, col: 14, offset: 391},
 									exprs: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 31, col: 14, offset: 375},
+											pos:  position{line: 31, col: 14, offset: 391},
 											name: "Module",
 										},
 										&litMatcher{
-											pos:        position{line: 31, col: 21, offset: 382},
+											pos:        position{line: 31, col: 21, offset: 398},
 											val:        ".",
 											ignoreCase: false,
 											want:       "\".\"",
@@ -59,15 +59,35 @@ var g = &grammar{
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 31, col: 27, offset: 388},
+							pos:   position{line: 31, col: 27, offset: 404},
 							label: "r",
-							expr: &ruleRefExpr{
-								pos:  position{line: 31, col: 29, offset: 390},
-								name: "ResourceSpec",
+							expr: &choiceExpr{
+								pos: position{line: 31, col: 30, offset: 407},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 31, col: 30, offset: 407},
+										name: "DataResourceSpec",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 31, col: 49, offset: 426},
+										name: "ResourceSpec",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 31, col: 63, offset: 440},
+							label: "d",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 31, col: 65, offset: 442},
+								expr: &ruleRefExpr{
+									pos:  position{line: 31, col: 65, offset: 442},
+									name: "Deposed",
+								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 31, col: 42, offset: 403},
+							pos:  position{line: 31, col: 74, offset: 451},
 							name: "EOF",
 						},
 					},
@@ -76,34 +96,34 @@ var g = &grammar{
 		},
 		{
 			name: "Module",
-			pos:  position{line: 44, col: 1, offset: 656},
+			pos:  position{line: 53, col: 1, offset: 1034},
 			expr: &actionExpr{
-				pos: position{line: 44, col: 10, offset: 665},
+				pos: position{line: 53, col: 10, offset: 1043},
 				run: (*parser).callonModule1,
 				expr: &seqExpr{
-					pos: position{line: 44, col: 10, offset: 665},
+					pos: position{line: 53, col: 10, offset: 1043},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 44, col: 10, offset: 665},
+							pos:        position{line: 53, col: 10, offset: 1043},
 							val:        "module.",
 							ignoreCase: false,
 							want:       "\"module.\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 44, col: 20, offset: 675},
+							pos:   position{line: 53, col: 20, offset: 1053},
 							label: "name",
 							expr: &ruleRefExpr{
-								pos:  position{line: 44, col: 25, offset: 680},
+								pos:  position{line: 53, col: 25, offset: 1058},
 								name: "Identifier",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 44, col: 36, offset: 691},
+							pos:   position{line: 53, col: 36, offset: 1069},
 							label: "i",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 44, col: 38, offset: 693},
+								pos: position{line: 53, col: 38, offset: 1071},
 								expr: &ruleRefExpr{
-									pos:  position{line: 44, col: 38, offset: 693},
+									pos:  position{line: 53, col: 38, offset: 1071},
 									name: "Index",
 								},
 							},
@@ -112,44 +132,71 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "DataResourceSpec",
+			pos:  position{line: 67, col: 1, offset: 1344},
+			expr: &actionExpr{
+				pos: position{line: 67, col: 20, offset: 1363},
+				run: (*parser).callonDataResourceSpec1,
+				expr: &seqExpr{
+					pos: position{line: 67, col: 20, offset: 1363},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 67, col: 20, offset: 1363},
+							val:        "data.",
+							ignoreCase: false,
+							want:       "\"data.\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 67, col: 28, offset: 1371},
+							label: "r",
+							expr: &ruleRefExpr{
+								pos:  position{line: 67, col: 30, offset: 1373},
+								name: "ResourceSpec",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "ResourceSpec",
-			pos:  position{line: 58, col: 1, offset: 961},
+			pos:  position{line: 74, col: 1, offset: 1515},
 			expr: &actionExpr{
-				pos: position{line: 58, col: 16, offset: 976},
+				pos: position{line: 74, col: 16, offset: 1530},
 				run: (*parser).callonResourceSpec1,
 				expr: &seqExpr{
-					pos: position{line: 58, col: 16, offset: 976},
+					pos: position{line: 74, col: 16, offset: 1530},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 58, col: 16, offset: 976},
+							pos:   position{line: 74, col: 16, offset: 1530},
 							label: "rType",
 							expr: &ruleRefExpr{
-								pos:  position{line: 58, col: 22, offset: 982},
+								pos:  position{line: 74, col: 22, offset: 1536},
 								name: "Identifier",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 58, col: 33, offset: 993},
+							pos:        position{line: 74, col: 33, offset: 1547},
 							val:        ".",
 							ignoreCase: false,
 							want:       "\".\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 58, col: 37, offset: 997},
+							pos:   position{line: 74, col: 37, offset: 1551},
 							label: "name",
 							expr: &ruleRefExpr{
-								pos:  position{line: 58, col: 42, offset: 1002},
+								pos:  position{line: 74, col: 42, offset: 1556},
 								name: "Identifier",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 58, col: 53, offset: 1013},
+							pos:   position{line: 74, col: 53, offset: 1567},
 							label: "i",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 58, col: 55, offset: 1015},
+								pos: position{line: 74, col: 55, offset: 1569},
 								expr: &ruleRefExpr{
-									pos:  position{line: 58, col: 55, offset: 1015},
+									pos:  position{line: 74, col: 55, offset: 1569},
 									name: "Index",
 								},
 							},
@@ -158,40 +205,375 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "Deposed",
+			pos:  position{line: 94, col: 1, offset: 2094},
+			expr: &actionExpr{
+				pos: position{line: 94, col: 11, offset: 2104},
+				run: (*parser).callonDeposed1,
+				expr: &seqExpr{
+					pos: position{line: 94, col: 11, offset: 2104},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 94, col: 11, offset: 2104},
+							val:        " (deposed object ",
+							ignoreCase: false,
+							want:       "\" (deposed object \"",
+						},
+						&labeledExpr{
+							pos:   position{line: 94, col: 31, offset: 2124},
+							label: "k",
+							expr: &ruleRefExpr{
+								pos:  position{line: 94, col: 33, offset: 2126},
+								name: "DeposedKey",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 94, col: 44, offset: 2137},
+							val:        ")",
+							ignoreCase: false,
+							want:       "\")\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "DeposedKey",
+			pos:  position{line: 98, col: 1, offset: 2164},
+			expr: &actionExpr{
+				pos: position{line: 98, col: 14, offset: 2177},
+				run: (*parser).callonDeposedKey1,
+				expr: &oneOrMoreExpr{
+					pos: position{line: 98, col: 14, offset: 2177},
+					expr: &ruleRefExpr{
+						pos:  position{line: 98, col: 14, offset: 2177},
+						name: "HexDigit",
+					},
+				},
+			},
+		},
+		{
+			name: "ModuleAddress",
+			pos:  position{line: 108, col: 1, offset: 2390},
+			expr: &actionExpr{
+				pos: position{line: 108, col: 17, offset: 2406},
+				run: (*parser).callonModuleAddress1,
+				expr: &seqExpr{
+					pos: position{line: 108, col: 17, offset: 2406},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 108, col: 17, offset: 2406},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 108, col: 23, offset: 2412},
+								name: "Module",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 108, col: 30, offset: 2419},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 108, col: 35, offset: 2424},
+								expr: &seqExpr{
+									pos: position{line: 108, col: 36, offset: 2425},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 108, col: 36, offset: 2425},
+											val:        ".",
+											ignoreCase: false,
+											want:       "\".\"",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 108, col: 40, offset: 2429},
+											name: "Module",
+										},
+									},
+								},
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 108, col: 49, offset: 2438},
+							name: "EOF",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ProviderConfig",
+			pos:  position{line: 118, col: 1, offset: 2753},
+			expr: &actionExpr{
+				pos: position{line: 118, col: 18, offset: 2770},
+				run: (*parser).callonProviderConfig1,
+				expr: &seqExpr{
+					pos: position{line: 118, col: 18, offset: 2770},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 118, col: 18, offset: 2770},
+							label: "m",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 118, col: 20, offset: 2772},
+								expr: &seqExpr{
+									pos: position{line: 118, col: 21, offset: 2773},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 118, col: 21, offset: 2773},
+											name: "Module",
+										},
+										&litMatcher{
+											pos:        position{line: 118, col: 28, offset: 2780},
+											val:        ".",
+											ignoreCase: false,
+											want:       "\".\"",
+										},
+									},
+								},
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 118, col: 34, offset: 2786},
+							val:        "provider",
+							ignoreCase: false,
+							want:       "\"provider\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 118, col: 45, offset: 2797},
+							label: "p",
+							expr: &choiceExpr{
+								pos: position{line: 118, col: 48, offset: 2800},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 118, col: 48, offset: 2800},
+										name: "LegacyProvider",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 118, col: 65, offset: 2817},
+										name: "ProviderSource",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 118, col: 81, offset: 2833},
+							label: "a",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 118, col: 83, offset: 2835},
+								expr: &seqExpr{
+									pos: position{line: 118, col: 84, offset: 2836},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 118, col: 84, offset: 2836},
+											val:        ".",
+											ignoreCase: false,
+											want:       "\".\"",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 118, col: 88, offset: 2840},
+											name: "Identifier",
+										},
+									},
+								},
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 118, col: 101, offset: 2853},
+							name: "EOF",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "LegacyProvider",
+			pos:  position{line: 133, col: 1, offset: 3162},
+			expr: &actionExpr{
+				pos: position{line: 133, col: 18, offset: 3179},
+				run: (*parser).callonLegacyProvider1,
+				expr: &seqExpr{
+					pos: position{line: 133, col: 18, offset: 3179},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 133, col: 18, offset: 3179},
+							val:        ".",
+							ignoreCase: false,
+							want:       "\".\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 133, col: 22, offset: 3183},
+							label: "t",
+							expr: &ruleRefExpr{
+								pos:  position{line: 133, col: 24, offset: 3185},
+								name: "Identifier",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ProviderSource",
+			pos:  position{line: 137, col: 1, offset: 3244},
+			expr: &actionExpr{
+				pos: position{line: 137, col: 18, offset: 3261},
+				run: (*parser).callonProviderSource1,
+				expr: &seqExpr{
+					pos: position{line: 137, col: 18, offset: 3261},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 137, col: 18, offset: 3261},
+							val:        "[",
+							ignoreCase: false,
+							want:       "\"[\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 137, col: 22, offset: 3265},
+							label: "s",
+							expr: &ruleRefExpr{
+								pos:  position{line: 137, col: 24, offset: 3267},
+								name: "String",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 137, col: 31, offset: 3274},
+							val:        "]",
+							ignoreCase: false,
+							want:       "\"]\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "RunReference",
+			pos:  position{line: 146, col: 1, offset: 3537},
+			expr: &actionExpr{
+				pos: position{line: 146, col: 16, offset: 3552},
+				run: (*parser).callonRunReference1,
+				expr: &seqExpr{
+					pos: position{line: 146, col: 16, offset: 3552},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 146, col: 16, offset: 3552},
+							val:        "run.",
+							ignoreCase: false,
+							want:       "\"run.\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 146, col: 23, offset: 3559},
+							label: "run",
+							expr: &ruleRefExpr{
+								pos:  position{line: 146, col: 27, offset: 3563},
+								name: "Identifier",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 146, col: 38, offset: 3574},
+							val:        ".",
+							ignoreCase: false,
+							want:       "\".\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 146, col: 42, offset: 3578},
+							label: "output",
+							expr: &ruleRefExpr{
+								pos:  position{line: 146, col: 49, offset: 3585},
+								name: "Identifier",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 146, col: 47, offset: 3583},
+							label: "t",
+							expr: &ruleRefExpr{
+								pos:  position{line: 146, col: 62, offset: 3598},
+								name: "Traversal",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 146, col: 72, offset: 3608},
+							name: "EOF",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Traversal",
+			pos:  position{line: 154, col: 1, offset: 3754},
+			expr: &actionExpr{
+				pos: position{line: 154, col: 13, offset: 3766},
+				run: (*parser).callonTraversal1,
+				expr: &zeroOrOneExpr{
+					pos: position{line: 154, col: 13, offset: 3766},
+					expr: &seqExpr{
+						pos: position{line: 154, col: 14, offset: 3767},
+						exprs: []interface{}{
+							&choiceExpr{
+								pos: position{line: 154, col: 15, offset: 3768},
+								alternatives: []interface{}{
+									&litMatcher{
+										pos:        position{line: 154, col: 15, offset: 3768},
+										val:        ".",
+										ignoreCase: false,
+										want:       "\".\"",
+									},
+									&litMatcher{
+										pos:        position{line: 154, col: 21, offset: 3774},
+										val:        "[",
+										ignoreCase: false,
+										want:       "\"[\"",
+									},
+								},
+							},
+							&zeroOrMoreExpr{
+								pos: position{line: 154, col: 26, offset: 3779},
+								expr: &anyMatcher{
+									line: 154, col: 26, offset: 3779,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Index",
-			pos:  position{line: 86, col: 1, offset: 1917},
+			pos:  position{line: 173, col: 1, offset: 4553},
 			expr: &actionExpr{
-				pos: position{line: 86, col: 9, offset: 1925},
+				pos: position{line: 173, col: 9, offset: 4561},
 				run: (*parser).callonIndex1,
 				expr: &seqExpr{
-					pos: position{line: 86, col: 9, offset: 1925},
+					pos: position{line: 173, col: 9, offset: 4561},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 86, col: 9, offset: 1925},
+							pos:        position{line: 173, col: 9, offset: 4561},
 							val:        "[",
 							ignoreCase: false,
 							want:       "\"[\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 86, col: 13, offset: 1929},
+							pos:   position{line: 173, col: 13, offset: 4565},
 							label: "i",
 							expr: &choiceExpr{
-								pos: position{line: 86, col: 16, offset: 1932},
+								pos: position{line: 173, col: 16, offset: 4568},
 								alternatives: []interface{}{
 									&ruleRefExpr{
-										pos:  position{line: 86, col: 16, offset: 1932},
+										pos:  position{line: 173, col: 16, offset: 4568},
 										name: "Integer",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 86, col: 26, offset: 1942},
+										pos:  position{line: 173, col: 26, offset: 4578},
 										name: "String",
 									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 35, offset: 4587},
+										name: "UnknownKey",
+									},
 								},
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 86, col: 34, offset: 1950},
+							pos:        position{line: 173, col: 47, offset: 4599},
 							val:        "]",
 							ignoreCase: false,
 							want:       "\"]\"",
@@ -200,55 +582,69 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "UnknownKey",
+			pos:  position{line: 177, col: 1, offset: 4639},
+			expr: &actionExpr{
+				pos: position{line: 177, col: 14, offset: 4652},
+				run: (*parser).callonUnknownKey1,
+				expr: &litMatcher{
+					pos:        position{line: 177, col: 14, offset: 4652},
+					val:        "*",
+					ignoreCase: false,
+					want:       "\"*\"",
+				},
+			},
+		},
 		{
 			name: "String",
-			pos:  position{line: 90, col: 1, offset: 1990},
+			pos:  position{line: 181, col: 1, offset: 4690},
 			expr: &choiceExpr{
-				pos: position{line: 90, col: 10, offset: 1999},
+				pos: position{line: 181, col: 10, offset: 4699},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 90, col: 10, offset: 1999},
+						pos: position{line: 181, col: 10, offset: 4699},
 						run: (*parser).callonString2,
 						expr: &seqExpr{
-							pos: position{line: 90, col: 10, offset: 1999},
+							pos: position{line: 181, col: 10, offset: 4699},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 90, col: 10, offset: 1999},
+									pos:        position{line: 181, col: 10, offset: 4699},
 									val:        "\"",
 									ignoreCase: false,
 									want:       "\"\\\"\"",
 								},
 								&zeroOrMoreExpr{
-									pos: position{line: 90, col: 14, offset: 2003},
+									pos: position{line: 181, col: 14, offset: 4703},
 									expr: &choiceExpr{
-										pos: position{line: 90, col: 16, offset: 2005},
+										pos: position{line: 181, col: 16, offset: 4705},
 										alternatives: []interface{}{
 											&seqExpr{
-												pos: position{line: 90, col: 16, offset: 2005},
+												pos: position{line: 181, col: 16, offset: 4705},
 												exprs: []interface{}{
 													&notExpr{
-														pos: position{line: 90, col: 16, offset: 2005},
+														pos: position{line: 181, col: 16, offset: 4705},
 														expr: &ruleRefExpr{
-															pos:  position{line: 90, col: 17, offset: 2006},
+															pos:  position{line: 181, col: 17, offset: 4706},
 															name: "EscapedChar",
 														},
 													},
 													&anyMatcher{
-														line: 90, col: 29, offset: 2018,
+														line: 181, col: 29, offset: 4718,
 													},
 												},
 											},
 											&seqExpr{
-												pos: position{line: 90, col: 33, offset: 2022},
+												pos: position{line: 181, col: 33, offset: 4722},
 												exprs: []interface{}{
 													&litMatcher{
-														pos:        position{line: 90, col: 33, offset: 2022},
+														pos:        position{line: 181, col: 33, offset: 4722},
 														val:        "\\",
 														ignoreCase: false,
 														want:       "\"\\\\\"",
 													},
 													&ruleRefExpr{
-														pos:  position{line: 90, col: 38, offset: 2027},
+														pos:  position{line: 181, col: 38, offset: 4727},
 														name: "EscapeSequence",
 													},
 												},
@@ -257,7 +653,7 @@ var g = &grammar{
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 90, col: 56, offset: 2045},
+									pos:        position{line: 181, col: 56, offset: 4745},
 									val:        "\"",
 									ignoreCase: false,
 									want:       "\"\\\"\"",
@@ -266,48 +662,48 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 93, col: 5, offset: 2164},
+						pos: position{line: 184, col: 5, offset: 4864},
 						run: (*parser).callonString15,
 						expr: &seqExpr{
-							pos: position{line: 93, col: 5, offset: 2164},
+							pos: position{line: 184, col: 5, offset: 4864},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 93, col: 5, offset: 2164},
+									pos:        position{line: 184, col: 5, offset: 4864},
 									val:        "\"",
 									ignoreCase: false,
 									want:       "\"\\\"\"",
 								},
 								&zeroOrMoreExpr{
-									pos: position{line: 93, col: 9, offset: 2168},
+									pos: position{line: 184, col: 9, offset: 4868},
 									expr: &choiceExpr{
-										pos: position{line: 93, col: 11, offset: 2170},
+										pos: position{line: 184, col: 11, offset: 4870},
 										alternatives: []interface{}{
 											&seqExpr{
-												pos: position{line: 93, col: 11, offset: 2170},
+												pos: position{line: 184, col: 11, offset: 4870},
 												exprs: []interface{}{
 													&notExpr{
-														pos: position{line: 93, col: 11, offset: 2170},
+														pos: position{line: 184, col: 11, offset: 4870},
 														expr: &ruleRefExpr{
-															pos:  position{line: 93, col: 12, offset: 2171},
+															pos:  position{line: 184, col: 12, offset: 4871},
 															name: "EscapedChar",
 														},
 													},
 													&anyMatcher{
-														line: 93, col: 24, offset: 2183,
+														line: 184, col: 24, offset: 4883,
 													},
 												},
 											},
 											&seqExpr{
-												pos: position{line: 93, col: 28, offset: 2187},
+												pos: position{line: 184, col: 28, offset: 4887},
 												exprs: []interface{}{
 													&litMatcher{
-														pos:        position{line: 93, col: 28, offset: 2187},
+														pos:        position{line: 184, col: 28, offset: 4887},
 														val:        "\\",
 														ignoreCase: false,
 														want:       "\"\\\\\"",
 													},
 													&ruleRefExpr{
-														pos:  position{line: 93, col: 33, offset: 2192},
+														pos:  position{line: 184, col: 33, offset: 4892},
 														name: "EscapeSequence",
 													},
 												},
@@ -316,9 +712,9 @@ var g = &grammar{
 									},
 								},
 								&notExpr{
-									pos: position{line: 93, col: 51, offset: 2210},
+									pos: position{line: 184, col: 51, offset: 4910},
 									expr: &litMatcher{
-										pos:        position{line: 93, col: 52, offset: 2211},
+										pos:        position{line: 184, col: 52, offset: 4911},
 										val:        "\"",
 										ignoreCase: false,
 										want:       "\"\\\"\"",
@@ -332,15 +728,15 @@ var g = &grammar{
 		},
 		{
 			name: "Identifier",
-			pos:  position{line: 104, col: 1, offset: 2526},
+			pos:  position{line: 195, col: 1, offset: 5226},
 			expr: &actionExpr{
-				pos: position{line: 104, col: 14, offset: 2539},
+				pos: position{line: 195, col: 14, offset: 5239},
 				run: (*parser).callonIdentifier1,
 				expr: &seqExpr{
-					pos: position{line: 104, col: 14, offset: 2539},
+					pos: position{line: 195, col: 14, offset: 5239},
 					exprs: []interface{}{
 						&charClassMatcher{
-							pos:        position{line: 104, col: 14, offset: 2539},
+							pos:        position{line: 195, col: 14, offset: 5239},
 							val:        "[a-z_-]i",
 							chars:      []rune{'_', '-'},
 							ranges:     []rune{'a', 'z'},
@@ -348,9 +744,9 @@ var g = &grammar{
 							inverted:   false,
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 104, col: 23, offset: 2548},
+							pos: position{line: 195, col: 23, offset: 5248},
 							expr: &charClassMatcher{
-								pos:        position{line: 104, col: 23, offset: 2548},
+								pos:        position{line: 195, col: 23, offset: 5248},
 								val:        "[a-zA-Z0-9_-]i",
 								chars:      []rune{'_', '-'},
 								ranges:     []rune{'a', 'z', 'a', 'z', '0', '9'},
@@ -364,26 +760,26 @@ var g = &grammar{
 		},
 		{
 			name: "Integer",
-			pos:  position{line: 108, col: 1, offset: 2600},
+			pos:  position{line: 199, col: 1, offset: 5300},
 			expr: &actionExpr{
-				pos: position{line: 108, col: 11, offset: 2610},
+				pos: position{line: 199, col: 11, offset: 5310},
 				run: (*parser).callonInteger1,
 				expr: &seqExpr{
-					pos: position{line: 108, col: 11, offset: 2610},
+					pos: position{line: 199, col: 11, offset: 5310},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 108, col: 11, offset: 2610},
+							pos: position{line: 199, col: 11, offset: 5310},
 							expr: &litMatcher{
-								pos:        position{line: 108, col: 11, offset: 2610},
+								pos:        position{line: 199, col: 11, offset: 5310},
 								val:        "-",
 								ignoreCase: false,
 								want:       "\"-\"",
 							},
 						},
 						&oneOrMoreExpr{
-							pos: position{line: 108, col: 16, offset: 2615},
+							pos: position{line: 199, col: 16, offset: 5315},
 							expr: &charClassMatcher{
-								pos:        position{line: 108, col: 16, offset: 2615},
+								pos:        position{line: 199, col: 16, offset: 5315},
 								val:        "[0-9]",
 								ranges:     []rune{'0', '9'},
 								ignoreCase: false,
@@ -396,9 +792,9 @@ var g = &grammar{
 		},
 		{
 			name: "EscapedChar",
-			pos:  position{line: 112, col: 1, offset: 2667},
+			pos:  position{line: 203, col: 1, offset: 5367},
 			expr: &charClassMatcher{
-				pos:        position{line: 112, col: 15, offset: 2681},
+				pos:        position{line: 203, col: 15, offset: 5381},
 				val:        "[\\x00-\\x1f\"\\\\]",
 				chars:      []rune{'"', '\\'},
 				ranges:     []rune{'\x00', '\x1f'},
@@ -408,16 +804,16 @@ var g = &grammar{
 		},
 		{
 			name: "EscapeSequence",
-			pos:  position{line: 114, col: 1, offset: 2697},
+			pos:  position{line: 205, col: 1, offset: 5397},
 			expr: &choiceExpr{
-				pos: position{line: 114, col: 18, offset: 2714},
+				pos: position{line: 205, col: 18, offset: 5414},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 114, col: 18, offset: 2714},
+						pos:  position{line: 205, col: 18, offset: 5414},
 						name: "SingleCharEscape",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 114, col: 37, offset: 2733},
+						pos:  position{line: 205, col: 37, offset: 5433},
 						name: "UnicodeEscape",
 					},
 				},
@@ -425,9 +821,9 @@ var g = &grammar{
 		},
 		{
 			name: "SingleCharEscape",
-			pos:  position{line: 116, col: 1, offset: 2748},
+			pos:  position{line: 207, col: 1, offset: 5448},
 			expr: &charClassMatcher{
-				pos:        position{line: 116, col: 20, offset: 2767},
+				pos:        position{line: 207, col: 20, offset: 5467},
 				val:        "[\"\\\\/bfnrt]",
 				chars:      []rune{'"', '\\', '/', 'b', 'f', 'n', 'r', 't'},
 				ignoreCase: false,
@@ -436,30 +832,30 @@ var g = &grammar{
 		},
 		{
 			name: "UnicodeEscape",
-			pos:  position{line: 118, col: 1, offset: 2780},
+			pos:  position{line: 209, col: 1, offset: 5480},
 			expr: &seqExpr{
-				pos: position{line: 118, col: 17, offset: 2796},
+				pos: position{line: 209, col: 17, offset: 5496},
 				exprs: []interface{}{
 					&litMatcher{
-						pos:        position{line: 118, col: 17, offset: 2796},
+						pos:        position{line: 209, col: 17, offset: 5496},
 						val:        "u",
 						ignoreCase: false,
 						want:       "\"u\"",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 118, col: 21, offset: 2800},
+						pos:  position{line: 209, col: 21, offset: 5500},
 						name: "HexDigit",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 118, col: 30, offset: 2809},
+						pos:  position{line: 209, col: 30, offset: 5509},
 						name: "HexDigit",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 118, col: 39, offset: 2818},
+						pos:  position{line: 209, col: 39, offset: 5518},
 						name: "HexDigit",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 118, col: 48, offset: 2827},
+						pos:  position{line: 209, col: 48, offset: 5527},
 						name: "HexDigit",
 					},
 				},
@@ -467,9 +863,9 @@ var g = &grammar{
 		},
 		{
 			name: "HexDigit",
-			pos:  position{line: 120, col: 1, offset: 2837},
+			pos:  position{line: 211, col: 1, offset: 5537},
 			expr: &charClassMatcher{
-				pos:        position{line: 120, col: 12, offset: 2848},
+				pos:        position{line: 211, col: 12, offset: 5548},
 				val:        "[0-9a-f]i",
 				ranges:     []rune{'0', '9', 'a', 'f'},
 				ignoreCase: true,
@@ -478,33 +874,42 @@ var g = &grammar{
 		},
 		{
 			name: "EOF",
-			pos:  position{line: 122, col: 1, offset: 2859},
+			pos:  position{line: 213, col: 1, offset: 5559},
 			expr: &notExpr{
-				pos: position{line: 122, col: 7, offset: 2865},
+				pos: position{line: 213, col: 7, offset: 5565},
 				expr: &anyMatcher{
-					line: 122, col: 8, offset: 2866,
+					line: 213, col: 8, offset: 5566,
 				},
 			},
 		},
 	},
 }
 
-func (c *current) onAddress1(m, r interface{}) (interface{}, error) {
+func (c *current) onAddress1(m, r, d interface{}) (interface{}, error) {
 	mi := toIfaceSlice(m)
 	v := make(ModulePath, len(mi))
 	for i, mp := range mi {
 		v[i] = toIfaceSlice(mp)[0].(Module)
 	}
-	return &Address{
-		v,
-		r.(ResourceSpec),
-	}, nil
+	rs := r.(ResourceSpec)
+	if rs.Mode == ManagedResourceMode && rs.Type == "data" {
+		// `data.foo` lacks either the type or the name of a data resource.
+		return nil, errors.New("data resources must have a type and a name")
+	}
+	a := &Address{
+		ModulePath:   v,
+		ResourceSpec: rs,
+	}
+	if d != nil {
+		a.DeposedKey = d.(string)
+	}
+	return a, nil
 }
 
 func (p *parser) callonAddress1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onAddress1(stack["m"], stack["r"])
+	return p.cur.onAddress1(stack["m"], stack["r"], stack["d"])
 }
 
 func (c *current) onModule1(name, i interface{}) (interface{}, error) {
@@ -526,6 +931,18 @@ func (p *parser) callonModule1() (interface{}, error) {
 	return p.cur.onModule1(stack["name"], stack["i"])
 }
 
+func (c *current) onDataResourceSpec1(r interface{}) (interface{}, error) {
+	rs := r.(ResourceSpec)
+	rs.Mode = DataResourceMode
+	return rs, nil
+}
+
+func (p *parser) callonDataResourceSpec1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onDataResourceSpec1(stack["r"])
+}
+
 func (c *current) onResourceSpec1(rType, name, i interface{}) (interface{}, error) {
 	if i != nil {
 		return ResourceSpec{
@@ -547,6 +964,109 @@ func (p *parser) callonResourceSpec1() (interface{}, error) {
 	return p.cur.onResourceSpec1(stack["rType"], stack["name"], stack["i"])
 }
 
+func (c *current) onDeposed1(k interface{}) (interface{}, error) {
+	return k, nil
+}
+
+func (p *parser) callonDeposed1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onDeposed1(stack["k"])
+}
+
+func (c *current) onDeposedKey1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonDeposedKey1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onDeposedKey1()
+}
+
+func (c *current) onModuleAddress1(first, rest interface{}) (interface{}, error) {
+	v := ModulePath{first.(Module)}
+	for _, mp := range toIfaceSlice(rest) {
+		v = append(v, toIfaceSlice(mp)[1].(Module))
+	}
+	return &Address{ModulePath: v}, nil
+}
+
+func (p *parser) callonModuleAddress1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onModuleAddress1(stack["first"], stack["rest"])
+}
+
+func (c *current) onProviderConfig1(m, p, a interface{}) (interface{}, error) {
+	var v ModulePath
+	for _, mp := range toIfaceSlice(m) {
+		v = append(v, toIfaceSlice(mp)[0].(Module))
+	}
+	pc := &ProviderConfig{
+		ModulePath: v,
+		Provider:   p.(Provider),
+	}
+	if a != nil {
+		pc.Alias = toIfaceSlice(a)[1].(string)
+	}
+	return pc, nil
+}
+
+func (p *parser) callonProviderConfig1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onProviderConfig1(stack["m"], stack["p"], stack["a"])
+}
+
+func (c *current) onLegacyProvider1(t interface{}) (interface{}, error) {
+	return Provider{Type: t.(string)}, nil
+}
+
+func (p *parser) callonLegacyProvider1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onLegacyProvider1(stack["t"])
+}
+
+func (c *current) onProviderSource1(s interface{}) (interface{}, error) {
+	source, _ := s.(string)
+	if strings.Count(source, "/") != 2 {
+		return Provider{}, errors.New("provider source must be fully qualified")
+	}
+	return ParseProvider(source)
+}
+
+func (p *parser) callonProviderSource1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onProviderSource1(stack["s"])
+}
+
+func (c *current) onRunReference1(run, output, t interface{}) (interface{}, error) {
+	return &RunReference{
+		Run:       run.(string),
+		Output:    output.(string),
+		Traversal: t.(string),
+	}, nil
+}
+
+func (p *parser) callonRunReference1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRunReference1(stack["run"], stack["output"], stack["t"])
+}
+
+func (c *current) onTraversal1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonTraversal1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onTraversal1()
+}
+
 func (c *current) onIndex1(i interface{}) (interface{}, error) {
 	return Index{Value: i}, nil
 }
@@ -557,6 +1077,16 @@ func (p *parser) callonIndex1() (interface{}, error) {
 	return p.cur.onIndex1(stack["i"])
 }
 
+func (c *current) onUnknownKey1() (interface{}, error) {
+	return UnknownKey{}, nil
+}
+
+func (p *parser) callonUnknownKey1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onUnknownKey1()
+}
+
 func (c *current) onString2() (interface{}, error) {
 	c.text = bytes.Replace(c.text, []byte(`\/`), []byte(`/`), -1)
 	return strconv.Unquote(string(c.text))
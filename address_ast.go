@@ -14,14 +14,21 @@ import (
 )
 
 // Address holds the parsed components of a Terraform address.
+//
+// An Address with an empty ResourceSpec refers to the whole module at its
+// ModulePath. The parser never returns such addresses, but they may be
+// constructed to target modules, e.g. when locking.
 type Address struct {
 	ModulePath   ModulePath
 	ResourceSpec ResourceSpec
+	// DeposedKey identifies a deposed object of the resource instance. It is
+	// empty for current objects.
+	DeposedKey string
 }
 
 // NewAddress parses the given address `a` into an Address struct. Returns an
 // error if we find a malformed address.
-// [module path][resource spec]
+// [module path][resource spec][deposed object]
 func NewAddress(a string) (*Address, error) {
 	addr, err := Parse(a, []byte(a))
 	if err != nil {
@@ -30,23 +37,119 @@ func NewAddress(a string) (*Address, error) {
 	return addr.(*Address), nil
 }
 
+// Contains reports whether a selects o, following the semantics of `-target`.
+// A resource without an index contains all of its instances and an unknown
+// index may stand for any instance key, in module steps as well as on the
+// resource. Both addresses must be in the same module instance, modulo
+// unknown keys. An address without a deposed key contains the deposed
+// objects of its instances. A module address contains every address within
+// the module subtree selected by ModulePath.Contains.
+func (a *Address) Contains(o *Address) bool {
+	if a.IsModule() {
+		return a.ModulePath.Contains(o.ModulePath)
+	}
+	if len(a.ModulePath) != len(o.ModulePath) {
+		return false
+	}
+	for i, step := range a.ModulePath {
+		if step.Name != o.ModulePath[i].Name || !step.Index.contains(o.ModulePath[i].Index) {
+			return false
+		}
+	}
+	if a.ResourceSpec.Mode != o.ResourceSpec.Mode || a.ResourceSpec.Type != o.ResourceSpec.Type || a.ResourceSpec.Name != o.ResourceSpec.Name {
+		return false
+	}
+	if a.ResourceSpec.Index.Value != nil && !a.ResourceSpec.Index.contains(o.ResourceSpec.Index) {
+		return false
+	}
+	return a.DeposedKey == "" || a.DeposedKey == o.DeposedKey
+}
+
+// Parent returns a copy of the address that most closely contains a: the
+// resource for an instance, the module for a resource, and the parent module
+// for a module. A deposed object's parent is its instance. Returns nil for
+// the root module.
+func (a *Address) Parent() *Address {
+	b := a.Clone()
+	switch {
+	case b.DeposedKey != "":
+		b.DeposedKey = ""
+	case !b.IsModule() && b.ResourceSpec.Index.Value != nil:
+		b.ResourceSpec.Index = Index{}
+	case !b.IsModule():
+		b.ResourceSpec = ResourceSpec{}
+	case len(b.ModulePath) > 0 && b.ModulePath[len(b.ModulePath)-1].Index.Value != nil:
+		b.ModulePath[len(b.ModulePath)-1].Index = Index{}
+	case len(b.ModulePath) > 0:
+		b.ModulePath = b.ModulePath[:len(b.ModulePath)-1]
+	default:
+		return nil
+	}
+	return b
+}
+
+// IsModule reports whether a refers to a whole module rather than to a
+// resource.
+func (a *Address) IsModule() bool {
+	return a.ResourceSpec == ResourceSpec{}
+}
+
+// Less reports whether a sorts before o in the order Terraform lists
+// resource instances: by module path, then managed resources before data
+// resources, then by type, name and instance key. Deposed objects sort after
+// the current object of their instance.
+func (a *Address) Less(o *Address) bool {
+	return a.compare(o) < 0
+}
+
+func (a *Address) compare(o *Address) int {
+	for i := 0; i < len(a.ModulePath) && i < len(o.ModulePath); i++ {
+		if c := strings.Compare(a.ModulePath[i].Name, o.ModulePath[i].Name); c != 0 {
+			return c
+		}
+		if c := a.ModulePath[i].Index.compare(o.ModulePath[i].Index); c != 0 {
+			return c
+		}
+	}
+	if len(a.ModulePath) != len(o.ModulePath) {
+		return len(a.ModulePath) - len(o.ModulePath)
+	}
+	if a.ResourceSpec.Mode != o.ResourceSpec.Mode {
+		return int(a.ResourceSpec.Mode) - int(o.ResourceSpec.Mode)
+	}
+	if c := strings.Compare(a.ResourceSpec.Type, o.ResourceSpec.Type); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.ResourceSpec.Name, o.ResourceSpec.Name); c != 0 {
+		return c
+	}
+	if c := a.ResourceSpec.Index.compare(o.ResourceSpec.Index); c != 0 {
+		return c
+	}
+	return strings.Compare(a.DeposedKey, o.DeposedKey)
+}
+
 // Clone copies the memory containing the address structure.
 func (a *Address) Clone() *Address {
 	mp := make(ModulePath, len(a.ModulePath))
 	copy(mp, a.ModulePath)
 	return &Address{
-		mp,
-		a.ResourceSpec,
+		ModulePath:   mp,
+		ResourceSpec: a.ResourceSpec,
+		DeposedKey:   a.DeposedKey,
 	}
 }
 
-// String representation of the address.
+// String representation of the address. Deposed objects are suffixed with
+// ` (deposed object <key>)` as in Terraform's human-readable output.
 func (a *Address) String() string {
-	var prefix string
-	if len(a.ModulePath) > 0 {
-		prefix = a.ModulePath.String() + "."
-	}
-	return prefix + a.ResourceSpec.String()
+	return string(a.AppendText(nil))
+}
+
+// instanceString is the address of the resource instance, without any
+// deposed object suffix.
+func (a *Address) instanceString() string {
+	return string(a.appendInstance(nil))
 }
 
 // ModulePath holds a list of modules contained in the address. The furthest
@@ -55,32 +158,105 @@ type ModulePath []Module
 
 // String representation of the path component of an address.
 func (m ModulePath) String() string {
-	modules := make([]string, len(m))
-	for i, c := range m {
-		modules[i] = c.String()
+	return string(m.appendText(nil))
+}
+
+// Contains reports whether the module instance at m contains the one at o,
+// following the semantics of `-target`. Every step of m must match the
+// corresponding step of o, except that a final step without an index
+// contains all instances of that module. An unknown index matches any key.
+func (m ModulePath) Contains(o ModulePath) bool {
+	if len(o) < len(m) {
+		return false
+	}
+	for i, step := range m {
+		if step.Name != o[i].Name {
+			return false
+		}
+		if i == len(m)-1 && step.Index.Value == nil {
+			return true
+		}
+		if !step.Index.contains(o[i].Index) {
+			return false
+		}
 	}
-	return strings.Join(modules, ".")
+	return true
 }
 
-// Index of either a module or a resource. Can either be an int or a string.
+// Index of either a module or a resource. Can either be an int, a string or
+// UnknownKey.
 type Index struct {
 	Value interface{}
 }
 
+// UnknownKey is the value of an index whose instance key is not yet known,
+// as in the partially-expanded address `module.a[*].foo.bar`. An unknown
+// index is distinct from an absent one: the former stands for a single
+// instance we cannot name yet, the latter for the resource or module as a
+// whole.
+type UnknownKey struct{}
+
 // String representation of an index. If the index is a string, it will be
-// quoted and escaped using go's string escaping semantics.
+// quoted and escaped using go's string escaping semantics. An unknown index
+// is represented as `*`.
 func (i *Index) String() string {
-	if i == nil || i.Value == nil {
+	if i == nil {
 		return ""
 	}
+	return string(i.appendText(nil))
+}
+
+// IsUnknown reports whether the index is present but its key is not yet
+// known.
+func (i *Index) IsUnknown() bool {
+	_, ok := i.Value.(UnknownKey)
+	return ok
+}
+
+// compare orders indexes as Terraform orders instance keys: absent first,
+// then integers, then strings, each by value. Unknown keys sort last.
+func (i Index) compare(o Index) int {
+	if c := i.rank() - o.rank(); c != 0 {
+		return c
+	}
 	switch v := i.Value.(type) {
 	case int:
-		return fmt.Sprintf("%d", v)
+		switch w := o.Value.(int); {
+		case v < w:
+			return -1
+		case v > w:
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		return strings.Compare(v, o.Value.(string))
+	default:
+		return 0
+	}
+}
+
+func (i Index) rank() int {
+	switch i.Value.(type) {
+	case nil:
+		return 0
+	case int:
+		return 1
 	case string:
-		return fmt.Sprintf("%q", v)
+		return 2
 	default:
-		panic(fmt.Errorf("got unknown type %T", v))
+		return 3
+	}
+}
+
+// contains reports whether the key of i selects the key of o. Keys must match
+// exactly unless i is unknown, in which case it may stand for any key, but
+// not for an absent one.
+func (i Index) contains(o Index) bool {
+	if i.IsUnknown() {
+		return o.Value != nil
 	}
+	return i == o
 }
 
 // Module represents a module component of an address.
@@ -95,24 +271,49 @@ type Module struct {
 // String representation of the module. The literal `module.` will be
 // prepended.
 func (m *Module) String() string {
-	if idx := m.Index.String(); idx != "" {
-		return fmt.Sprintf("module.%s[%s]", m.Name, idx)
+	return string(m.appendText(nil))
+}
+
+// ResourceMode distinguishes managed resources from data resources.
+type ResourceMode int
+
+const (
+	// ManagedResourceMode is the mode of resources declared by `resource`
+	// blocks.
+	ManagedResourceMode ResourceMode = iota
+	// DataResourceMode is the mode of resources declared by `data` blocks.
+	// Their addresses are prefixed with `data.`.
+	DataResourceMode
+)
+
+// String representation of the mode, as used in Terraform's JSON output.
+func (m ResourceMode) String() string {
+	switch m {
+	case ManagedResourceMode:
+		return "managed"
+	case DataResourceMode:
+		return "data"
+	default:
+		panic(fmt.Errorf("got unknown mode %d", int(m)))
 	}
-	return fmt.Sprintf("module.%s", m.Name)
 }
 
 // ResourceSpec describes the resource of an address.
-// resource_type.resource_name[resource index]
+// [data.]resource_type.resource_name[resource index]
 type ResourceSpec struct {
+	Mode  ResourceMode
 	Type  string
 	Name  string
 	Index Index
 }
 
+// Equal reports whether r and o refer to the same resource, or the same
+// instance of it.
+func (r ResourceSpec) Equal(o ResourceSpec) bool {
+	return r == o
+}
+
 // String representation of the resource component of an address.
 func (r *ResourceSpec) String() string {
-	if idx := r.Index.String(); idx != "" {
-		return fmt.Sprintf("%s.%s[%s]", r.Type, r.Name, idx)
-	}
-	return fmt.Sprintf("%s.%s", r.Type, r.Name)
+	return string(r.appendText(nil))
 }
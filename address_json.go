@@ -0,0 +1,70 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonAddress mirrors the address fields of a resource change or resource
+// instance in Terraform's JSON output format.
+type jsonAddress struct {
+	Address       string      `json:"address"`
+	ModuleAddress string      `json:"module_address,omitempty"`
+	Mode          string      `json:"mode"`
+	Type          string      `json:"type"`
+	Name          string      `json:"name"`
+	Index         interface{} `json:"index,omitempty"`
+	IndexUnknown  bool        `json:"index_unknown,omitempty"`
+	Deposed       string      `json:"deposed,omitempty"`
+}
+
+// jsonValue is the value of `index` in Terraform's JSON output format. An
+// unknown key has no JSON representation and is omitted; it is marked by
+// `index_unknown` instead.
+func (i *Index) jsonValue() interface{} {
+	if i.IsUnknown() {
+		return nil
+	}
+	return i.Value
+}
+
+// MarshalJSON encodes the address using the field names of Terraform's JSON
+// plan format. As in that format, `address` never carries the deposed object
+// suffix; the deposed key is reported separately as `deposed`. An unknown
+// instance key, which that format cannot represent, is reported as
+// `"index_unknown": true` without an `index`, so that `foo.bar[*]` can be
+// told apart from `foo.bar` without parsing `address`.
+func (a *Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonAddress{
+		Address:       a.instanceString(),
+		ModuleAddress: a.ModulePath.String(),
+		Mode:          a.ResourceSpec.Mode.String(),
+		Type:          a.ResourceSpec.Type,
+		Name:          a.ResourceSpec.Name,
+		Index:         a.ResourceSpec.Index.jsonValue(),
+		IndexUnknown:  a.ResourceSpec.Index.IsUnknown(),
+		Deposed:       a.DeposedKey,
+	})
+}
+
+// UnmarshalJSON decodes an address from the field names of Terraform's JSON
+// plan format. The `address` field is authoritative; `deposed`, when present,
+// sets the deposed key.
+func (a *Address) UnmarshalJSON(b []byte) error {
+	var v jsonAddress
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	addr, err := NewAddress(v.Address)
+	if err != nil {
+		return err
+	}
+	if v.Deposed != "" {
+		if addr.DeposedKey != "" && addr.DeposedKey != v.Deposed {
+			return fmt.Errorf("address %q does not match deposed key %q", v.Address, v.Deposed)
+		}
+		addr.DeposedKey = v.Deposed
+	}
+	*a = *addr
+	return nil
+}
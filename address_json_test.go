@@ -0,0 +1,56 @@
+package address
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	var tests = []struct {
+		given    string
+		expected string
+	}{
+		{`foo.bar`, `{"address":"foo.bar","mode":"managed","type":"foo","name":"bar"}`},
+		{`module.a[0].foo.bar["x"]`, `{"address":"module.a[0].foo.bar[\"x\"]","module_address":"module.a[0]","mode":"managed","type":"foo","name":"bar","index":"x"}`},
+		{`module.a[*].foo.bar[*]`, `{"address":"module.a[*].foo.bar[*]","module_address":"module.a[*]","mode":"managed","type":"foo","name":"bar","index_unknown":true}`},
+		{`module.a.data.foo.bar`, `{"address":"module.a.data.foo.bar","module_address":"module.a","mode":"data","type":"foo","name":"bar"}`},
+		{`foo.bar[1] (deposed object 1a2b3c4d)`, `{"address":"foo.bar[1]","mode":"managed","type":"foo","name":"bar","index":1,"deposed":"1a2b3c4d"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			a, err := NewAddress(tt.given)
+			require.NoError(t, err)
+			b, err := json.Marshal(a)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(b))
+
+			var c Address
+			require.NoError(t, json.Unmarshal(b, &c))
+			require.Equal(t, a, &c)
+		})
+	}
+}
+
+func TestUnmarshalJSONResourceChange(t *testing.T) {
+	change := `{
+		"address": "module.a.aws_instance.a",
+		"module_address": "module.a",
+		"mode": "managed",
+		"type": "aws_instance",
+		"name": "a",
+		"deposed": "1a2b3c4d",
+		"change": {"actions": ["delete"]}
+	}`
+	var a Address
+	require.NoError(t, json.Unmarshal([]byte(change), &a))
+	require.Equal(t, "1a2b3c4d", a.DeposedKey)
+	require.Equal(t, `module.a.aws_instance.a (deposed object 1a2b3c4d)`, a.String())
+}
+
+func TestUnmarshalJSONDeposedMismatch(t *testing.T) {
+	var a Address
+	err := json.Unmarshal([]byte(`{"address":"foo.bar (deposed object 1a2b3c4d)","deposed":"00000000"}`), &a)
+	require.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package address
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AddressTemplate is an address with `$name` placeholders, such as
+//
+//	module.app[$env].module.db[$region].aws_db_instance.$name
+//
+// A placeholder stands for a whole module name, resource type or resource
+// name, or for an instance key. The same placeholder may appear more than
+// once, and then stands for the same value each time.
+type AddressTemplate struct {
+	source string
+	// addr is the template parsed with the placeholders replaced by
+	// sentinels; params maps each sentinel to its placeholder.
+	addr   *Address
+	params map[string]string
+}
+
+// Sentinels replacing placeholders. Identifiers are replaced by an unlikely
+// identifier, and keys by a string key starting with a NUL character, which
+// would have to be escaped in an actual address.
+const (
+	templateIdentSentinel = "__tfaddr_param_"
+	templateKeySentinel   = "\x00"
+)
+
+// ParseAddressTemplate parses an address template. Returns an error if the
+// template is malformed.
+func ParseAddressTemplate(s string) (*AddressTemplate, error) {
+	if strings.Contains(s, templateIdentSentinel) {
+		return nil, fmt.Errorf("invalid address template %q: must not contain %q", s, templateIdentSentinel)
+	}
+	t := &AddressTemplate{source: s, params: make(map[string]string)}
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+			continue
+		case c == '"':
+			quoted = !quoted
+		case c == '$' && !quoted:
+			n := i + 1
+			for n < len(s) && isIdentRune(rune(s[n]), n == i+1) && s[n] != '-' {
+				n++
+			}
+			name := s[i+1 : n]
+			if name == "" {
+				return nil, fmt.Errorf("invalid address template %q: missing placeholder name at offset %d", s, i)
+			}
+			before, after := byte('.'), byte('.')
+			if i > 0 {
+				before = s[i-1]
+			}
+			if n < len(s) {
+				after = s[n]
+			}
+			sentinel := strconv.Itoa(len(t.params))
+			switch {
+			case before == '[' && after == ']':
+				b.WriteString(`"\u0000` + sentinel + `"`)
+				sentinel = templateKeySentinel + sentinel
+			case before == '.' && (after == '.' || after == '['):
+				sentinel = templateIdentSentinel + sentinel
+				b.WriteString(sentinel)
+			default:
+				return nil, fmt.Errorf("invalid address template %q: placeholder $%s must be a whole name or key", s, name)
+			}
+			t.params[sentinel] = name
+			i = n - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	addr, err := NewAddress(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid address template %q: %w", s, err)
+	}
+	t.addr = addr
+	return t, nil
+}
+
+// String returns the template as it was parsed.
+func (t *AddressTemplate) String() string {
+	return t.source
+}
+
+// Params returns the names of the placeholders of the template, sorted.
+func (t *AddressTemplate) Params() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range t.params {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bind returns the address with each placeholder replaced by its value in
+// params. Placeholders of names must be bound to string values that are
+// valid identifiers. Returns an error if a placeholder is not bound.
+func (t *AddressTemplate) Bind(params map[string]Index) (*Address, error) {
+	a := t.addr.Clone()
+	for _, s := range templateSlots(a) {
+		name, ok := t.param(s)
+		if !ok {
+			continue
+		}
+		v, ok := params[name]
+		if !ok || v.Value == nil {
+			return nil, fmt.Errorf("address template %q: placeholder $%s is not bound", t.source, name)
+		}
+		if s.ident == nil {
+			*s.index = v
+			continue
+		}
+		ident, ok := v.Value.(string)
+		if !ok || !isIdentifier(ident) {
+			return nil, fmt.Errorf("address template %q: placeholder $%s must be bound to a name, got %s", t.source, name, v.String())
+		}
+		*s.ident = ident
+	}
+	return a, nil
+}
+
+// Unbind matches a against the template and returns the value of each
+// placeholder. Returns false if a does not match, or if a placeholder that
+// appears more than once would take different values.
+func (t *AddressTemplate) Unbind(a *Address) (map[string]Index, bool) {
+	if len(a.ModulePath) != len(t.addr.ModulePath) || a.ResourceSpec.Mode != t.addr.ResourceSpec.Mode || a.DeposedKey != t.addr.DeposedKey {
+		return nil, false
+	}
+	params := make(map[string]Index)
+	want := templateSlots(t.addr)
+	got := templateSlots(a)
+	for i, s := range want {
+		var v Index
+		if s.ident != nil {
+			v = Index{Value: *got[i].ident}
+		} else {
+			v = *got[i].index
+		}
+		name, ok := t.param(s)
+		switch {
+		case !ok && s.ident != nil && *s.ident != *got[i].ident:
+			return nil, false
+		case !ok && s.index != nil && *s.index != v:
+			return nil, false
+		case !ok:
+			continue
+		}
+		if v.Value == nil {
+			return nil, false
+		}
+		if prev, ok := params[name]; ok && prev != v {
+			return nil, false
+		}
+		params[name] = v
+	}
+	return params, true
+}
+
+// param returns the name of the placeholder in s, if any.
+func (t *AddressTemplate) param(s templateSlot) (string, bool) {
+	var sentinel string
+	if s.ident != nil {
+		sentinel = *s.ident
+	} else if key, ok := s.index.Value.(string); ok {
+		sentinel = key
+	}
+	name, ok := t.params[sentinel]
+	return name, ok
+}
+
+// templateSlot points to a name or an index of an address that may hold a
+// placeholder. Exactly one of ident and index is set.
+type templateSlot struct {
+	ident *string
+	index *Index
+}
+
+// templateSlots returns the slots of a in order, so that the slots of two
+// addresses with as many module steps correspond.
+func templateSlots(a *Address) []templateSlot {
+	var slots []templateSlot
+	for i := range a.ModulePath {
+		m := &a.ModulePath[i]
+		slots = append(slots, templateSlot{ident: &m.Name}, templateSlot{index: &m.Index})
+	}
+	r := &a.ResourceSpec
+	return append(slots,
+		templateSlot{ident: &r.Type},
+		templateSlot{ident: &r.Name},
+		templateSlot{index: &r.Index},
+	)
+}
@@ -0,0 +1,110 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressTemplateBind(t *testing.T) {
+	var tests = []struct {
+		template string
+		params   map[string]Index
+		expected string
+	}{
+		{
+			`module.app[$env].module.db[$region].aws_db_instance.main`,
+			map[string]Index{"env": {Value: "prod"}, "region": {Value: "eu-west-1"}},
+			`module.app["prod"].module.db["eu-west-1"].aws_db_instance.main`,
+		},
+		{
+			`module.$mod.data.$type.$name[$i]`,
+			map[string]Index{"mod": {Value: "net"}, "type": {Value: "aws_vpc"}, "name": {Value: "main"}, "i": {Value: 2}},
+			`module.net.data.aws_vpc.main[2]`,
+		},
+		{
+			`module.a[$k].foo.bar[$k]`,
+			map[string]Index{"k": {Value: 1}, "unused": {Value: 2}},
+			`module.a[1].foo.bar[1]`,
+		},
+		{
+			`foo.bar["$literal"]`,
+			nil,
+			`foo.bar["$literal"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			tmpl, err := ParseAddressTemplate(tt.template)
+			require.NoError(t, err)
+			require.Equal(t, tt.template, tmpl.String())
+			a, err := tmpl.Bind(tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, a.String())
+
+			params, ok := tmpl.Unbind(a)
+			require.True(t, ok)
+			for _, name := range tmpl.Params() {
+				require.Equal(t, tt.params[name], params[name], name)
+			}
+			require.Len(t, params, len(tmpl.Params()))
+		})
+	}
+}
+
+func TestAddressTemplateBindInvalid(t *testing.T) {
+	tmpl, err := ParseAddressTemplate(`module.$mod.foo.bar[$i]`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"i", "mod"}, tmpl.Params())
+
+	_, err = tmpl.Bind(map[string]Index{"mod": {Value: "a"}})
+	require.EqualError(t, err, `address template "module.$mod.foo.bar[$i]": placeholder $i is not bound`)
+	_, err = tmpl.Bind(map[string]Index{"mod": {Value: 1}, "i": {Value: 1}})
+	require.EqualError(t, err, `address template "module.$mod.foo.bar[$i]": placeholder $mod must be bound to a name, got 1`)
+	_, err = tmpl.Bind(map[string]Index{"mod": {Value: "a b"}, "i": {Value: 1}})
+	require.Error(t, err)
+}
+
+func TestAddressTemplateUnbind(t *testing.T) {
+	tmpl, err := ParseAddressTemplate(`module.app[$env].aws_instance.$name[$env]`)
+	require.NoError(t, err)
+
+	var tests = []struct {
+		addr     string
+		expected map[string]Index
+	}{
+		{`module.app["dev"].aws_instance.web["dev"]`, map[string]Index{"env": {Value: "dev"}, "name": {Value: "web"}}},
+		{`module.app["dev"].aws_instance.web["prod"]`, nil},
+		{`module.app["dev"].aws_instance.web`, nil},
+		{`module.app.aws_instance.web`, nil},
+		{`module.other["dev"].aws_instance.web["dev"]`, nil},
+		{`module.app["dev"].aws_eip.web["dev"]`, nil},
+		{`module.app["dev"].data.aws_instance.web["dev"]`, nil},
+		{`aws_instance.web["dev"]`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			a, err := NewAddress(tt.addr)
+			require.NoError(t, err)
+			params, ok := tmpl.Unbind(a)
+			require.Equal(t, tt.expected != nil, ok)
+			require.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+func TestParseAddressTemplateInvalid(t *testing.T) {
+	for _, s := range []string{
+		`foo.$`,
+		`foo.bar-$x`,
+		`foo.bar[$x `,
+		`module.a[x$y].foo.bar`,
+		`$type`,
+		`foo.__tfaddr_param_0`,
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseAddressTemplate(s)
+			require.Error(t, err)
+		})
+	}
+}
@@ -29,6 +29,13 @@ func TestValidAddresses(t *testing.T) {
 		{`module.a[0].foo.bar[0]`},
 		{`module.a[0].module.b.foo.bar`},
 		{`module.a[0].module.b.foo.bar[0]`},
+		{`foo.bar (deposed object 1a2b3c4d)`},
+		{`module.a["xyz"].foo.bar[0] (deposed object 1a2b3c4d)`},
+		{`module.a[*].foo.bar`},
+		{`module.a[*].module.b["xyz"].foo.bar[*]`},
+		{`data.foo.bar`},
+		{`module.a.data.foo.bar[0]`},
+		{`data_foo.bar`},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -49,6 +56,7 @@ func TestIndex(t *testing.T) {
 	}{
 		{"string", `"foo"`, `module.foo["foo"].a.b["foo"]`},
 		{"int", "123", "module.foo[123].a.b[123]"},
+		{"unknown", "*", "module.foo[*].a.b[*]"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -91,6 +99,14 @@ func TestInvalidAddresses(t *testing.T) {
 		{`foo["xyz]`},
 		{`module.foo.bar`},
 		{`module.a.foo.bar["x"yz"]`},
+		{`foo.bar (deposed object )`},
+		{`foo.bar (deposed object xyz)`},
+		{`foo.bar(deposed object 1a2b3c4d)`},
+		{`foo.bar[**]`},
+		{`foo.bar[]`},
+		{`data.foo.bar.baz`},
+		{`data.foo`},
+		{`module.a.data.foo[0]`},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -127,3 +143,146 @@ func TestCopy(t *testing.T) {
 	require.Equal(t, expected, b.String())
 	require.Equal(t, orig, a.String())
 }
+
+func TestDeposed(t *testing.T) {
+	a, err := NewAddress(`module.a.foo.bar[0] (deposed object 1a2b3c4d)`)
+	require.NoError(t, err)
+	require.Equal(t, "1a2b3c4d", a.DeposedKey)
+	require.Equal(t, "bar", a.ResourceSpec.Name)
+	require.Equal(t, 0, a.ResourceSpec.Index.Value)
+
+	b := a.Clone()
+	b.DeposedKey = ""
+	require.Equal(t, `module.a.foo.bar[0]`, b.String())
+	require.Equal(t, "1a2b3c4d", a.DeposedKey)
+}
+
+func TestUnknownIndex(t *testing.T) {
+	a, err := NewAddress(`module.a[*].foo.bar`)
+	require.NoError(t, err)
+	require.True(t, a.ModulePath[0].Index.IsUnknown())
+	require.False(t, a.ResourceSpec.Index.IsUnknown())
+
+	b, err := NewAddress(`module.a["*"].foo.bar`)
+	require.NoError(t, err)
+	require.False(t, b.ModulePath[0].Index.IsUnknown())
+	require.NotEqual(t, a.String(), b.String())
+}
+
+func TestContains(t *testing.T) {
+	var tests = []struct {
+		a        string
+		o        string
+		expected bool
+	}{
+		{`foo.bar`, `foo.bar`, true},
+		{`foo.bar`, `foo.bar[0]`, true},
+		{`foo.bar`, `foo.bar[*]`, true},
+		{`foo.bar`, `foo.baz`, false},
+		{`foo.bar[0]`, `foo.bar`, false},
+		{`foo.bar[0]`, `foo.bar[1]`, false},
+		{`foo.bar[0]`, `foo.bar[*]`, false},
+		{`foo.bar[*]`, `foo.bar[0]`, true},
+		{`foo.bar[*]`, `foo.bar["a"]`, true},
+		{`foo.bar[*]`, `foo.bar`, false},
+		{`module.a.foo.bar`, `module.a[0].foo.bar`, false},
+		{`module.a[*].foo.bar`, `module.a[0].foo.bar[1]`, true},
+		{`module.a[*].foo.bar`, `module.a.foo.bar`, false},
+		{`module.a[0].foo.bar`, `module.a[*].foo.bar`, false},
+		{`module.a.foo.bar`, `module.a.module.b.foo.bar`, false},
+		{`foo.bar[0]`, `foo.bar[0] (deposed object 1a2b3c4d)`, true},
+		{`foo.bar[0] (deposed object 1a2b3c4d)`, `foo.bar[0]`, false},
+		{`data.foo.bar`, `data.foo.bar[0]`, true},
+		{`data.foo.bar`, `foo.bar`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.o, func(t *testing.T) {
+			a, err := NewAddress(tt.a)
+			require.NoError(t, err)
+			o, err := NewAddress(tt.o)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, a.Contains(o))
+		})
+	}
+}
+
+func TestModulePathContains(t *testing.T) {
+	var tests = []struct {
+		a        string
+		o        string
+		expected bool
+	}{
+		{`module.a.x.y`, `module.a.x.y`, true},
+		{`module.a.x.y`, `module.a[0].x.y`, true},
+		{`module.a.x.y`, `module.a[*].module.b.x.y`, true},
+		{`module.a[0].x.y`, `module.a[1].x.y`, false},
+		{`module.a[*].x.y`, `module.a[1].module.b.x.y`, true},
+		{`module.a[0].x.y`, `module.a[*].x.y`, false},
+		{`module.a.module.b.x.y`, `module.a[0].module.b.x.y`, false},
+		{`module.a.module.b.x.y`, `module.a.x.y`, false},
+		{`x.y`, `module.a.x.y`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.o, func(t *testing.T) {
+			a, err := NewAddress(tt.a)
+			require.NoError(t, err)
+			o, err := NewAddress(tt.o)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, a.ModulePath.Contains(o.ModulePath))
+		})
+	}
+}
+
+func TestDataResource(t *testing.T) {
+	a, err := NewAddress(`module.a.data.foo.bar[0]`)
+	require.NoError(t, err)
+	require.Equal(t, DataResourceMode, a.ResourceSpec.Mode)
+	require.Equal(t, "foo", a.ResourceSpec.Type)
+	require.Equal(t, "bar", a.ResourceSpec.Name)
+
+	// Terraform reserves `data` as a resource type, so `data.foo` is not a
+	// managed resource of type data.
+	_, err = NewAddress(`data.foo`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "data resources must have a type and a name")
+}
+
+func TestLess(t *testing.T) {
+	sorted := []string{
+		`aws_instance.a`,
+		`aws_instance.a[-9223372036854775808]`,
+		`aws_instance.a[0]`,
+		`aws_instance.a[2]`,
+		`aws_instance.a[10]`,
+		`aws_instance.a["a"]`,
+		`aws_instance.a["a"] (deposed object 1a2b3c4d)`,
+		`aws_instance.a[*]`,
+		`aws_instance.b`,
+		`aws_s3_bucket.a`,
+		`data.aws_ami.a`,
+		`module.a.aws_instance.a`,
+		`module.a[0].aws_instance.a`,
+		`module.a[0].module.b.aws_instance.a`,
+		`module.a[1].aws_instance.a`,
+		`module.b.aws_instance.a`,
+	}
+	for i := range sorted {
+		for j := range sorted {
+			a, err := NewAddress(sorted[i])
+			require.NoError(t, err)
+			b, err := NewAddress(sorted[j])
+			require.NoError(t, err)
+			require.Equal(t, i < j, a.Less(b), "%s < %s", a, b)
+		}
+	}
+}
+
+func TestModuleAddress(t *testing.T) {
+	m := &Address{ModulePath: ModulePath{{Name: "a", Index: Index{0}}}}
+	require.True(t, m.IsModule())
+	require.Equal(t, "module.a[0]", m.String())
+	require.True(t, m.Contains(parseAll(t, `module.a[0].module.b.foo.bar`)[0]))
+	require.False(t, m.Contains(parseAll(t, `module.a[1].foo.bar`)[0]))
+	require.False(t, parseAll(t, `module.a[0].foo.bar`)[0].Contains(m))
+	require.True(t, (&Address{}).Contains(m))
+}
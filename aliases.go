@@ -0,0 +1,159 @@
+package address
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Aliases records resource types that providers have renamed, so that
+// addresses using an old name can be recognized and migrated. A type may be
+// renamed to a single new type, such as `aws_alb` to `aws_lb`, or split into
+// several, such as `azurerm_virtual_machine` into
+// `azurerm_linux_virtual_machine` and `azurerm_windows_virtual_machine`.
+// The zero value has no aliases and is ready to use.
+type Aliases struct {
+	renamed map[string][]string
+}
+
+// KnownAliases returns a registry of well-known resource type renames.
+func KnownAliases() *Aliases {
+	al := &Aliases{}
+	al.Add("aws_alb", "aws_lb")
+	al.Add("aws_alb_listener", "aws_lb_listener")
+	al.Add("aws_alb_listener_certificate", "aws_lb_listener_certificate")
+	al.Add("aws_alb_listener_rule", "aws_lb_listener_rule")
+	al.Add("aws_alb_target_group", "aws_lb_target_group")
+	al.Add("aws_alb_target_group_attachment", "aws_lb_target_group_attachment")
+	al.Add("azurerm_virtual_machine", "azurerm_linux_virtual_machine", "azurerm_windows_virtual_machine")
+	al.Add("azurerm_virtual_machine_scale_set", "azurerm_linux_virtual_machine_scale_set", "azurerm_windows_virtual_machine_scale_set")
+	return al
+}
+
+// Add declares that the resource type old was renamed to current, or split
+// into each of current.
+func (al *Aliases) Add(old string, current ...string) {
+	if al.renamed == nil {
+		al.renamed = make(map[string][]string)
+	}
+	al.renamed[old] = append(al.renamed[old], current...)
+}
+
+// Current returns the current names of the resource type typ, following
+// renames transitively. A type that was never renamed is its own current
+// name. A type renamed in a cycle has no current name. A nil al has no
+// aliases.
+func (al *Aliases) Current(typ string) []string {
+	if al == nil {
+		return []string{typ}
+	}
+	seen := make(map[string]bool)
+	var current []string
+	var resolve func(t string)
+	resolve = func(t string) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		next := al.renamed[t]
+		if len(next) == 0 {
+			current = append(current, t)
+			return
+		}
+		for _, n := range next {
+			resolve(n)
+		}
+	}
+	resolve(typ)
+	sort.Strings(current)
+	return current
+}
+
+// EquivalentTypes reports whether the resource types a and b name the same
+// kind of resource: they are equal, or one was renamed to the other, or both
+// were renamed to a common type. A nil al only reports equal types as
+// equivalent.
+func (al *Aliases) EquivalentTypes(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if al == nil {
+		return false
+	}
+	cb := al.Current(b)
+	for _, t := range al.Current(a) {
+		for _, u := range cb {
+			if t == u {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Equal reports whether r and o are equal, consulting al for equivalent
+// resource types. A nil al makes this the same as ResourceSpec.Equal.
+func (al *Aliases) Equal(r, o ResourceSpec) bool {
+	if al == nil {
+		return r.Equal(o)
+	}
+	return r.Mode == o.Mode && r.Name == o.Name && r.Index == o.Index && al.EquivalentTypes(r.Type, o.Type)
+}
+
+// Match reports whether p matches a or a under any equivalent resource type.
+// A nil al makes this the same as Pattern.Match.
+func (al *Aliases) Match(p *Pattern, a *Address) bool {
+	if p.Match(a) {
+		return true
+	}
+	if al == nil || a.IsModule() {
+		return false
+	}
+	b := a.Clone()
+	for _, t := range al.equivalents(a.ResourceSpec.Type) {
+		b.ResourceSpec.Type = t
+		if p.Match(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// equivalents returns the known resource types equivalent to typ, other
+// than typ itself.
+func (al *Aliases) equivalents(typ string) []string {
+	var types []string
+	seen := map[string]bool{typ: true}
+	add := func(t string) {
+		if !seen[t] && al.EquivalentTypes(t, typ) {
+			types = append(types, t)
+		}
+		seen[t] = true
+	}
+	for old, current := range al.renamed {
+		add(old)
+		for _, t := range current {
+			add(t)
+		}
+	}
+	return types
+}
+
+// Rewrite returns a copy of a with its resource type migrated to the current
+// name. Returns an error if the type was split, since the address could then
+// belong to any of the new types, or if it was renamed in a cycle. A nil al
+// returns a copy of a unchanged.
+func (al *Aliases) Rewrite(a *Address) (*Address, error) {
+	b := a.Clone()
+	if al == nil || a.IsModule() {
+		return b, nil
+	}
+	current := al.Current(a.ResourceSpec.Type)
+	if len(current) == 0 {
+		return nil, fmt.Errorf("resource type %s is renamed in a cycle; cannot rewrite %s", a.ResourceSpec.Type, a)
+	}
+	if len(current) != 1 {
+		return nil, fmt.Errorf("resource type %s was split into %v; cannot rewrite %s", a.ResourceSpec.Type, current, a)
+	}
+	b.ResourceSpec.Type = current[0]
+	return b, nil
+}
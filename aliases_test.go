@@ -0,0 +1,92 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasesCurrent(t *testing.T) {
+	al := KnownAliases()
+	al.Add("aws_elb_v0", "aws_alb")
+	require.Equal(t, []string{"aws_lb"}, al.Current("aws_alb"))
+	require.Equal(t, []string{"aws_lb"}, al.Current("aws_elb_v0"))
+	require.Equal(t, []string{"aws_instance"}, al.Current("aws_instance"))
+	require.Equal(t, []string{"azurerm_linux_virtual_machine", "azurerm_windows_virtual_machine"}, al.Current("azurerm_virtual_machine"))
+
+	// Cycles are tolerated.
+	var cyclic Aliases
+	cyclic.Add("a", "b")
+	cyclic.Add("b", "a")
+	require.Empty(t, cyclic.Current("a"))
+}
+
+func TestAliasesEqual(t *testing.T) {
+	al := KnownAliases()
+	var tests = []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{`aws_alb.a`, `aws_lb.a`, true},
+		{`aws_lb.a`, `aws_alb.a`, true},
+		{`aws_alb.a[0]`, `aws_lb.a[1]`, false},
+		{`aws_alb.a`, `aws_lb.b`, false},
+		{`data.aws_alb.a`, `aws_lb.a`, false},
+		{`azurerm_virtual_machine.a`, `azurerm_linux_virtual_machine.a`, true},
+		{`azurerm_linux_virtual_machine.a`, `azurerm_windows_virtual_machine.a`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, b := parseAll(t, tt.a)[0], parseAll(t, tt.b)[0]
+			require.Equal(t, tt.expected, al.Equal(a.ResourceSpec, b.ResourceSpec))
+			require.Equal(t, tt.a == tt.b, (*Aliases)(nil).Equal(a.ResourceSpec, b.ResourceSpec))
+		})
+	}
+}
+
+func TestAliasesMatch(t *testing.T) {
+	al := KnownAliases()
+	p, err := ParsePattern(`module.*.aws_lb.*`)
+	require.NoError(t, err)
+	a := parseAll(t, `module.a.aws_alb.x`)[0]
+	require.False(t, p.Match(a))
+	require.True(t, al.Match(p, a))
+	require.False(t, (*Aliases)(nil).Match(p, a))
+
+	p, err = ParsePattern(`aws_alb.x`)
+	require.NoError(t, err)
+	require.True(t, al.Match(p, parseAll(t, `aws_lb.x`)[0]))
+	require.False(t, al.Match(p, parseAll(t, `aws_instance.x`)[0]))
+}
+
+func TestAliasesRewrite(t *testing.T) {
+	al := KnownAliases()
+	a := parseAll(t, `module.a["x"].aws_alb_listener.l[0]`)[0]
+	b, err := al.Rewrite(a)
+	require.NoError(t, err)
+	require.Equal(t, `module.a["x"].aws_lb_listener.l[0]`, b.String())
+	require.Equal(t, `module.a["x"].aws_alb_listener.l[0]`, a.String())
+
+	_, err = al.Rewrite(parseAll(t, `azurerm_virtual_machine.vm`)[0])
+	require.Error(t, err)
+}
+
+func TestAliasesRewriteCycle(t *testing.T) {
+	al := &Aliases{}
+	al.Add("old_a", "old_b")
+	al.Add("old_b", "old_a")
+	require.Empty(t, al.Current("old_a"))
+	_, err := al.Rewrite(parseAll(t, `old_a.x`)[0])
+	require.EqualError(t, err, "resource type old_a is renamed in a cycle; cannot rewrite old_a.x")
+}
+
+func TestAliasesNil(t *testing.T) {
+	var al *Aliases
+	require.Equal(t, []string{"aws_alb"}, al.Current("aws_alb"))
+	require.True(t, al.EquivalentTypes("aws_alb", "aws_alb"))
+	require.False(t, al.EquivalentTypes("aws_alb", "aws_lb"))
+	b, err := al.Rewrite(parseAll(t, `aws_alb.x`)[0])
+	require.NoError(t, err)
+	require.Equal(t, `aws_alb.x`, b.String())
+}
@@ -0,0 +1,91 @@
+package address
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ParseCache is a bounded cache in front of NewAddress for programs that
+// parse the same addresses over and over, such as the `dependencies` of
+// every instance in a state. Least recently used addresses are evicted
+// first. It is safe for concurrent use.
+type ParseCache struct {
+	mu     sync.Mutex
+	size   int
+	order  *list.List
+	items  map[string]*list.Element
+	hits   uint64
+	misses uint64
+}
+
+type parseCacheEntry struct {
+	key  string
+	addr *Address
+	err  error
+}
+
+// CacheStats reports the effectiveness of a ParseCache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Len is the number of addresses currently cached.
+	Len int
+}
+
+// NewParseCache returns a cache holding at most size addresses.
+func NewParseCache(size int) *ParseCache {
+	if size < 1 {
+		size = 1
+	}
+	return &ParseCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// NewAddress is like the package-level NewAddress, but returns cached
+// results for addresses it has seen recently. Each call returns a fresh
+// copy, so callers may modify the result without affecting the cache.
+// Malformed addresses are cached too.
+func (c *ParseCache) NewAddress(s string) (*Address, error) {
+	c.mu.Lock()
+	if el, ok := c.items[s]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		e := el.Value.(*parseCacheEntry)
+		c.mu.Unlock()
+		return e.result()
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	addr, err := NewAddress(s)
+	e := &parseCacheEntry{key: s, addr: addr, err: err}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[s]; !ok {
+		c.items[s] = c.order.PushFront(e)
+		if c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*parseCacheEntry).key)
+		}
+	}
+	return e.result()
+}
+
+// Stats returns the number of hits and misses so far.
+func (c *ParseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.order.Len()}
+}
+
+func (e *parseCacheEntry) result() (*Address, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.addr.Clone(), nil
+}
@@ -0,0 +1,59 @@
+package address
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCache(t *testing.T) {
+	c := NewParseCache(2)
+
+	a, err := c.NewAddress(`module.a.foo.bar[0]`)
+	require.NoError(t, err)
+	a.ModulePath[0].Name = "modified"
+	a.ResourceSpec.Index = Index{1}
+
+	b, err := c.NewAddress(`module.a.foo.bar[0]`)
+	require.NoError(t, err)
+	require.Equal(t, `module.a.foo.bar[0]`, b.String())
+	require.Equal(t, CacheStats{Hits: 1, Misses: 1, Len: 1}, c.Stats())
+
+	_, err = c.NewAddress(`foo`)
+	require.Error(t, err)
+	_, err = c.NewAddress(`foo`)
+	require.Error(t, err)
+	require.Equal(t, CacheStats{Hits: 2, Misses: 2, Len: 2}, c.Stats())
+
+	// foo.bar evicts the least recently used module.a.foo.bar[0].
+	_, err = c.NewAddress(`foo.bar`)
+	require.NoError(t, err)
+	_, err = c.NewAddress(`module.a.foo.bar[0]`)
+	require.NoError(t, err)
+	require.Equal(t, CacheStats{Hits: 2, Misses: 4, Len: 2}, c.Stats())
+}
+
+func TestParseCacheConcurrent(t *testing.T) {
+	c := NewParseCache(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := fmt.Sprintf("foo.bar[%d]", (i+j)%16)
+				a, err := c.NewAddress(s)
+				if err != nil || a.String() != s {
+					t.Errorf("got %v, %v for %s", a, err, s)
+				}
+				a.ResourceSpec.Name = "modified"
+			}
+		}(i)
+	}
+	wg.Wait()
+	s := c.Stats()
+	require.Equal(t, uint64(800), s.Hits+s.Misses)
+	require.Equal(t, 8, s.Len)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	address "github.com/hashicorp/go-terraform-address"
+)
+
+// Requests and results of the exported functions. Addresses are passed as
+// strings, except that format also accepts the address objects of
+// Terraform's JSON plan format, as returned by parse.
+//
+//	tfaddr_parse    {"address": "module.a.foo.bar[0]"}
+//	                → {"result": {"address": "module.a.foo.bar[0]", "module_address": "module.a", ...}}
+//	tfaddr_format   {"address": {"address": "foo.bar", "deposed": "00000001"}}
+//	                → {"result": "foo.bar (deposed object 00000001)"}
+//	tfaddr_compare  {"a": "foo.bar[1]", "b": "foo.bar[0]"}
+//	                → {"result": 1}
+//	tfaddr_match    {"pattern": "**.foo.*", "address": "module.a.foo.bar"}
+//	                → {"result": true}
+//
+// Errors are returned as {"error": "message"}.
+
+type parseRequest struct {
+	Address string `json:"address"`
+}
+
+type formatRequest struct {
+	Address json.RawMessage `json:"address"`
+}
+
+type compareRequest struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type matchRequest struct {
+	Pattern string `json:"pattern"`
+	Address string `json:"address"`
+}
+
+type resultResponse struct {
+	Result interface{} `json:"result"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func parse(req []byte) []byte {
+	return handle(req, func(r *parseRequest) (interface{}, error) {
+		return address.NewAddress(r.Address)
+	})
+}
+
+func format(req []byte) []byte {
+	return handle(req, func(r *formatRequest) (interface{}, error) {
+		var s string
+		if err := json.Unmarshal(r.Address, &s); err == nil {
+			a, err := address.NewAddress(s)
+			if err != nil {
+				return nil, err
+			}
+			return a.String(), nil
+		}
+		var a address.Address
+		if err := json.Unmarshal(r.Address, &a); err != nil {
+			return nil, err
+		}
+		return a.String(), nil
+	})
+}
+
+func compare(req []byte) []byte {
+	return handle(req, func(r *compareRequest) (interface{}, error) {
+		a, err := address.NewAddress(r.A)
+		if err != nil {
+			return nil, err
+		}
+		b, err := address.NewAddress(r.B)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case a.Less(b):
+			return -1, nil
+		case b.Less(a):
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	})
+}
+
+func match(req []byte) []byte {
+	return handle(req, func(r *matchRequest) (interface{}, error) {
+		p, err := address.ParsePattern(r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		a, err := address.NewAddress(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		return p.Match(a), nil
+	})
+}
+
+// handle decodes a request of type T, calls fn and encodes its result or
+// error as a response.
+func handle[T any](req []byte, fn func(*T) (interface{}, error)) []byte {
+	var r T
+	var resp interface{}
+	if err := json.Unmarshal(req, &r); err != nil {
+		resp = errorResponse{Error: fmt.Sprintf("invalid request: %s", err)}
+	} else if result, err := fn(&r); err != nil {
+		resp = errorResponse{Error: err.Error()}
+	} else {
+		resp = resultResponse{Result: result}
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		b, _ = json.Marshal(errorResponse{Error: err.Error()})
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI(t *testing.T) {
+	var tests = []struct {
+		name     string
+		fn       func([]byte) []byte
+		req      string
+		expected string
+	}{
+		{
+			"parse",
+			parse,
+			`{"address": "module.a[0].data.foo.bar[\"x\"]"}`,
+			`{"result":{"address":"module.a[0].data.foo.bar[\"x\"]","module_address":"module.a[0]","mode":"data","type":"foo","name":"bar","index":"x"}}`,
+		},
+		{
+			"parse error",
+			parse,
+			`{"address": "foo"}`,
+			`{"error":"foo:1:4 (3): no match found, expected: \".\" or [a-zA-Z0-9_-]i"}`,
+		},
+		{
+			"format string",
+			format,
+			`{"address": "foo.bar[0] (deposed object 0000000a)"}`,
+			`{"result":"foo.bar[0] (deposed object 0000000a)"}`,
+		},
+		{
+			"format object",
+			format,
+			`{"address": {"address": "foo.bar", "deposed": "00000001"}}`,
+			`{"result":"foo.bar (deposed object 00000001)"}`,
+		},
+		{
+			"compare",
+			compare,
+			`{"a": "foo.bar[1]", "b": "foo.bar[0]"}`,
+			`{"result":1}`,
+		},
+		{
+			"compare equal",
+			compare,
+			`{"a": "foo.bar", "b": "foo.bar"}`,
+			`{"result":0}`,
+		},
+		{
+			"match",
+			match,
+			`{"pattern": "**.foo.*", "address": "module.a.foo.bar"}`,
+			`{"result":true}`,
+		},
+		{
+			"match false",
+			match,
+			`{"pattern": "foo.*", "address": "module.a.foo.bar"}`,
+			`{"result":false}`,
+		},
+		{
+			"invalid request",
+			match,
+			`[]`,
+			`{"error":"invalid request: json: cannot unmarshal array into Go value of type main.matchRequest"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, string(tt.fn([]byte(tt.req))))
+		})
+	}
+}
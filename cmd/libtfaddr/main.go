@@ -0,0 +1,48 @@
+// Command libtfaddr exposes the address parser as a C shared library, for use
+// from other languages:
+//
+//	go build -buildmode=c-shared -o libtfaddr.so ./cmd/libtfaddr
+//
+// Every exported function takes a JSON request and returns a JSON response,
+// which the caller must release with tfaddr_free. A response holds either
+// `result` or `error`. See api.go for the requests and results of each
+// function, and python/tfaddr.py for a Python binding.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "unsafe"
+
+//export tfaddr_parse
+func tfaddr_parse(req *C.char) *C.char {
+	return call(parse, req)
+}
+
+//export tfaddr_format
+func tfaddr_format(req *C.char) *C.char {
+	return call(format, req)
+}
+
+//export tfaddr_compare
+func tfaddr_compare(req *C.char) *C.char {
+	return call(compare, req)
+}
+
+//export tfaddr_match
+func tfaddr_match(req *C.char) *C.char {
+	return call(match, req)
+}
+
+//export tfaddr_free
+func tfaddr_free(resp *C.char) {
+	C.free(unsafe.Pointer(resp))
+}
+
+func call(fn func([]byte) []byte, req *C.char) *C.char {
+	return C.CString(string(fn([]byte(C.GoString(req)))))
+}
+
+func main() {}
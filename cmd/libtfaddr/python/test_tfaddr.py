@@ -0,0 +1,77 @@
+"""Tests for the tfaddr binding. Builds libtfaddr unless $TFADDR_LIBRARY is
+set:
+
+    python3 -m unittest discover -s cmd/libtfaddr/python
+"""
+
+import functools
+import os
+import shutil
+import subprocess
+import tempfile
+import unittest
+
+import tfaddr
+
+HERE = os.path.dirname(os.path.abspath(__file__))
+
+
+def setUpModule():
+    global _tmp
+    _tmp = None
+    if os.environ.get("TFADDR_LIBRARY"):
+        return
+    _tmp = tempfile.mkdtemp()
+    lib = os.path.join(_tmp, "libtfaddr.so")
+    subprocess.run(
+        ["go", "build", "-buildmode=c-shared", "-o", lib, "."],
+        cwd=os.path.dirname(HERE),
+        check=True,
+    )
+    tfaddr._lib = tfaddr._load(lib)
+
+
+def tearDownModule():
+    if _tmp:
+        shutil.rmtree(_tmp)
+
+
+class TestTfaddr(unittest.TestCase):
+    def test_parse(self):
+        self.assertEqual(
+            tfaddr.parse('module.a[0].data.foo.bar["x"]'),
+            {
+                "address": 'module.a[0].data.foo.bar["x"]',
+                "module_address": "module.a[0]",
+                "mode": "data",
+                "type": "foo",
+                "name": "bar",
+                "index": "x",
+            },
+        )
+
+    def test_parse_error(self):
+        with self.assertRaises(tfaddr.AddressError):
+            tfaddr.parse("foo")
+
+    def test_format(self):
+        a = tfaddr.parse("foo.bar[0] (deposed object 0000000a)")
+        self.assertEqual(tfaddr.format(a), "foo.bar[0] (deposed object 0000000a)")
+        self.assertEqual(tfaddr.format("module.a.foo.bar"), "module.a.foo.bar")
+
+    def test_compare(self):
+        addrs = ["foo.bar[10]", "data.foo.bar", "foo.bar[2]", "module.a.foo.bar"]
+        addrs.sort(key=functools.cmp_to_key(tfaddr.compare))
+        self.assertEqual(
+            addrs, ["foo.bar[2]", "foo.bar[10]", "data.foo.bar", "module.a.foo.bar"]
+        )
+
+    def test_match(self):
+        self.assertTrue(tfaddr.match("**.foo.*", "module.a.foo.bar"))
+        self.assertFalse(tfaddr.match("foo.*", "module.a.foo.bar"))
+        with self.assertRaises(tfaddr.AddressError):
+            tfaddr.match("foo.[", "foo.bar")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,77 @@
+"""Python binding for libtfaddr, the Terraform address parser.
+
+Build the shared library first:
+
+    go build -buildmode=c-shared -o libtfaddr.so ./cmd/libtfaddr
+
+The library is looked up at $TFADDR_LIBRARY, then next to this file.
+"""
+
+import ctypes
+import json
+import os
+
+__all__ = ["AddressError", "parse", "format", "compare", "match"]
+
+
+class AddressError(ValueError):
+    """Raised when an address or pattern is malformed."""
+
+
+def _load(path=None):
+    if path is None:
+        path = os.environ.get("TFADDR_LIBRARY") or os.path.join(
+            os.path.dirname(os.path.abspath(__file__)), "libtfaddr.so"
+        )
+    lib = ctypes.CDLL(path)
+    for name in ("tfaddr_parse", "tfaddr_format", "tfaddr_compare", "tfaddr_match"):
+        fn = getattr(lib, name)
+        fn.argtypes = [ctypes.c_char_p]
+        # Not c_char_p, which would copy the response and lose the pointer
+        # to free.
+        fn.restype = ctypes.c_void_p
+    lib.tfaddr_free.argtypes = [ctypes.c_void_p]
+    lib.tfaddr_free.restype = None
+    return lib
+
+
+_lib = None
+
+
+def _call(name, request):
+    global _lib
+    if _lib is None:
+        _lib = _load()
+    ptr = getattr(_lib, name)(json.dumps(request).encode("utf-8"))
+    try:
+        response = json.loads(ctypes.string_at(ptr).decode("utf-8"))
+    finally:
+        _lib.tfaddr_free(ptr)
+    if "error" in response:
+        raise AddressError(response["error"])
+    return response["result"]
+
+
+def parse(address):
+    """Parse an address into a dict with the field names of Terraform's JSON
+    plan format: address, module_address, mode, type, name, index and
+    deposed."""
+    return _call("tfaddr_parse", {"address": address})
+
+
+def format(address):
+    """Format an address, given as a string or as a dict returned by parse,
+    in its canonical form."""
+    return _call("tfaddr_format", {"address": address})
+
+
+def compare(a, b):
+    """Compare two addresses in the order Terraform lists them, returning
+    -1, 0 or 1. Use functools.cmp_to_key to sort addresses."""
+    return _call("tfaddr_compare", {"a": a, "b": b})
+
+
+def match(pattern, address):
+    """Report whether the pattern, such as `module.app-*.**`, matches the
+    address."""
+    return _call("tfaddr_match", {"pattern": pattern, "address": address})
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	address "github.com/hashicorp/go-terraform-address"
+)
+
+// result is returned to JavaScript, encoded as JSON. It holds either Result
+// or Error.
+type result struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  *jsError    `json:"error,omitempty"`
+}
+
+type jsError struct {
+	Message string `json:"message"`
+	// Spans locate the syntax errors of an address. They are empty for
+	// other errors.
+	Spans []span `json:"spans,omitempty"`
+}
+
+// span locates a syntax error. Start and End are indexes of UTF-16 code
+// units, as for String.prototype.slice, and End is after the offending
+// character, if any.
+type span struct {
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Expected []string `json:"expected,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func parse(s string) result {
+	a, err := address.NewAddress(s)
+	if err != nil {
+		return syntaxError(s, err)
+	}
+	return result{Result: a}
+}
+
+func normalize(s string) result {
+	a, err := address.NewAddress(s)
+	if err != nil {
+		return syntaxError(s, err)
+	}
+	return result{Result: a.String()}
+}
+
+func match(pattern, s string) result {
+	p, err := address.ParsePattern(pattern)
+	if err != nil {
+		return result{Error: &jsError{Message: err.Error()}}
+	}
+	a, err := address.NewAddress(s)
+	if err != nil {
+		return syntaxError(s, err)
+	}
+	return result{Result: p.Match(a)}
+}
+
+func syntaxError(s string, err error) result {
+	e := &jsError{Message: err.Error()}
+	for _, se := range address.SyntaxErrors(err) {
+		end := se.Offset
+		if end < len(s) {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		e.Spans = append(e.Spans, span{
+			Start:    utf16Len(s[:se.Offset]),
+			End:      utf16Len(s[:end]),
+			Line:     se.Line,
+			Column:   se.Column,
+			Expected: se.Expected,
+			Message:  se.Message,
+		})
+	}
+	return result{Error: e}
+}
+
+// utf16Len returns the length of s in JavaScript, in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
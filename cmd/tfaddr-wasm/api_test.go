@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI(t *testing.T) {
+	var tests = []struct {
+		name     string
+		result   result
+		expected string
+	}{
+		{
+			"parse",
+			parse(`module.a.foo.bar[0]`),
+			`{"result":{"address":"module.a.foo.bar[0]","module_address":"module.a","mode":"managed","type":"foo","name":"bar","index":0}}`,
+		},
+		{
+			"parse error",
+			parse(`foo.bar[x]`),
+			`{"error":{"message":"foo.bar[x]:1:9 (8): no match found, expected: \"*\", \"-\", \"\\\"\" or [0-9]","spans":[{"start":8,"end":9,"line":1,"column":9,"expected":["\"*\"","\"-\"","\"\\\"\"","[0-9]"],"message":"no match found"}]}}`,
+		},
+		{
+			"parse error at end",
+			parse(`foo`),
+			`{"error":{"message":"foo:1:4 (3): no match found, expected: \".\" or [a-zA-Z0-9_-]i","spans":[{"start":3,"end":3,"line":1,"column":4,"expected":["\".\"","[a-zA-Z0-9_-]i"],"message":"no match found"}]}}`,
+		},
+		{
+			"parse error after astral character",
+			parse(`foo.bar["😀"].x`),
+			`{"error":{"message":"foo.bar[\"😀\"].x:1:13 (15): no match found, expected: \" (deposed object \" or EOF","spans":[{"start":13,"end":14,"line":1,"column":13,"expected":["\" (deposed object \"","EOF"],"message":"no match found"}]}}`,
+		},
+		{
+			"normalize",
+			normalize(`data.foo.bar["x"]`),
+			`{"result":"data.foo.bar[\"x\"]"}`,
+		},
+		{
+			"match",
+			match(`module.*.**`, `module.a.foo.bar`),
+			`{"result":true}`,
+		},
+		{
+			"no match",
+			match(`foo.*`, `module.a.foo.bar`),
+			`{"result":false}`,
+		},
+		{
+			"invalid pattern",
+			match(`module.a`, `foo.bar`),
+			`{"error":{"message":"invalid pattern \"module.a\": must end with a resource or **"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(b))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+//go:build js && wasm
+
+// Command tfaddr-wasm exposes the address parser to JavaScript:
+//
+//	GOOS=js GOARCH=wasm go build -o playground/tfaddr.wasm ./cmd/tfaddr-wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" playground/
+//
+// Before Go 1.24, wasm_exec.js is in misc/wasm instead of lib/wasm.
+//
+// Once loaded, it defines a global `tfaddr` object with these functions, each
+// returning {result} or {error}:
+//
+//	tfaddr.parse(address)           the address fields, as in Terraform's JSON plan format
+//	tfaddr.normalize(address)       the address in its canonical form
+//	tfaddr.match(pattern, address)  whether the pattern matches the address
+//
+// Errors are {message, spans}, where spans locate syntax errors in the
+// address. See api.go. The playground directory holds a page to try them.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+)
+
+func main() {
+	js.Global().Set("tfaddr", js.ValueOf(map[string]interface{}{
+		"parse": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return toJS(parse(arg(args, 0)))
+		}),
+		"normalize": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return toJS(normalize(arg(args, 0)))
+		}),
+		"match": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return toJS(match(arg(args, 0), arg(args, 1)))
+		}),
+	}))
+	select {}
+}
+
+func arg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
+
+// toJS converts r to a JavaScript object through JSON, as js.ValueOf does
+// not convert structs.
+func toJS(r result) js.Value {
+	b, err := json.Marshal(r)
+	if err != nil {
+		b, _ = json.Marshal(result{Error: &jsError{Message: err.Error()}})
+	}
+	return js.Global().Get("JSON").Call("parse", string(b))
+}
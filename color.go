@@ -0,0 +1,235 @@
+package address
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by Renderer.
+const (
+	ansiReset    = "\x1b[0m"
+	ansiModule   = "\x1b[34m"
+	ansiMode     = "\x1b[2m"
+	ansiType     = "\x1b[36m"
+	ansiName     = "\x1b[32m"
+	ansiKey      = "\x1b[33m"
+	ansiDeposed  = "\x1b[35m"
+	ansiEmphasis = "\x1b[1;4;7m"
+)
+
+// Renderer renders addresses for terminals, coloring the module path,
+// resource type, resource name and instance keys distinctly.
+type Renderer struct {
+	// Color enables ANSI colors. Without colors, differences are marked
+	// with carets on a second line instead.
+	Color bool
+	// Width limits the rendered width in characters. Module paths are
+	// shortened from the left to fit, but the resource, or the last step of
+	// a module address, is always rendered in full. Zero means no limit.
+	Width int
+}
+
+// NewRenderer returns a renderer that uses colors unless the NO_COLOR
+// environment variable is set, as per https://no-color.org.
+func NewRenderer(width int) *Renderer {
+	return &Renderer{
+		Color: os.Getenv("NO_COLOR") == "",
+		Width: width,
+	}
+}
+
+// segment is a run of text of a single style in a rendered address.
+type segment struct {
+	text  string
+	style string
+	diff  bool
+}
+
+// Render renders a single address.
+func (r *Renderer) Render(a *Address) string {
+	segs := truncateSegments(addressSegments(a), r.Width)
+	return r.join(segs)
+}
+
+// RenderDiff renders from and to side by side, as in a `moved` block,
+// emphasizing the components that differ between them.
+func (r *Renderer) RenderDiff(from, to *Address) string {
+	const sep = "  →  "
+	left, right := addressSegments(from), addressSegments(to)
+	markDiff(left, right)
+
+	width := 0
+	if r.Width > 0 {
+		width = (r.Width - utf8.RuneCountInString(sep)) / 2
+		if width < 1 {
+			width = 1
+		}
+	}
+	left = truncateSegments(left, width)
+	right = truncateSegments(right, width)
+
+	line := r.join(left) + sep + r.join(right)
+	if r.Color {
+		return line
+	}
+	marks := carets(left) + strings.Repeat(" ", utf8.RuneCountInString(sep)) + carets(right)
+	if strings.TrimSpace(marks) == "" {
+		return line
+	}
+	return line + "\n" + strings.TrimRight(marks, " ")
+}
+
+func (r *Renderer) join(segs []segment) string {
+	var b strings.Builder
+	for _, s := range segs {
+		if !r.Color || s.style == "" || s.text == "" {
+			b.WriteString(s.text)
+			continue
+		}
+		b.WriteString(s.style)
+		if s.diff {
+			b.WriteString(ansiEmphasis)
+		}
+		b.WriteString(s.text)
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}
+
+// carets returns a line marking the differing segments with carets.
+func carets(segs []segment) string {
+	var b strings.Builder
+	for _, s := range segs {
+		c := " "
+		if s.diff {
+			c = "^"
+		}
+		b.WriteString(strings.Repeat(c, utf8.RuneCountInString(s.text)))
+	}
+	return b.String()
+}
+
+// addressSegments splits an address into styled segments. Styled segments
+// always come in the same order, possibly empty, so that they can be
+// compared: for each module step its name and key, then the resource mode,
+// type, name, key and deposed object.
+func addressSegments(a *Address) []segment {
+	var segs []segment
+	for i, m := range a.ModulePath {
+		if i > 0 {
+			segs = append(segs, segment{text: "."})
+		}
+		segs = append(segs,
+			segment{text: "module.", style: ansiModule},
+			segment{text: m.Name, style: ansiModule},
+			segment{text: bracketed(m.Index), style: ansiKey},
+		)
+	}
+	if a.IsModule() {
+		return segs
+	}
+	if len(a.ModulePath) > 0 {
+		segs = append(segs, segment{text: "."})
+	}
+	rs := a.ResourceSpec
+	var mode, deposed string
+	if rs.Mode == DataResourceMode {
+		mode = "data."
+	}
+	if a.DeposedKey != "" {
+		deposed = " (deposed object " + a.DeposedKey + ")"
+	}
+	return append(segs,
+		segment{text: mode, style: ansiMode},
+		segment{text: rs.Type, style: ansiType},
+		segment{text: "."},
+		segment{text: rs.Name, style: ansiName},
+		segment{text: bracketed(rs.Index), style: ansiKey},
+		segment{text: deposed, style: ansiDeposed},
+	)
+}
+
+func bracketed(i Index) string {
+	if i.Value == nil {
+		return ""
+	}
+	return "[" + i.String() + "]"
+}
+
+// markDiff marks the segments of a and b that differ. Module steps are
+// compared position by position from the left, and the resources are
+// compared with each other, so that an extra module step only marks that
+// step.
+func markDiff(a, b []segment) {
+	ma, ra := splitResource(a)
+	mb, rb := splitResource(b)
+	markPairs(ma, mb)
+	markPairs(ra, rb)
+}
+
+// splitResource splits segments into the module steps and the resource.
+func splitResource(segs []segment) ([]segment, []segment) {
+	for i, s := range segs {
+		if s.style == ansiMode {
+			return segs[:i], segs[i:]
+		}
+	}
+	return segs, nil
+}
+
+// markPairs compares the styled segments of a and b pairwise.
+func markPairs(a, b []segment) {
+	ia, ib := styledIndexes(a), styledIndexes(b)
+	for n := 0; n < len(ia) || n < len(ib); n++ {
+		switch {
+		case n >= len(ia):
+			b[ib[n]].diff = b[ib[n]].text != ""
+		case n >= len(ib):
+			a[ia[n]].diff = a[ia[n]].text != ""
+		case a[ia[n]].text != b[ib[n]].text || a[ia[n]].style != b[ib[n]].style:
+			a[ia[n]].diff = a[ia[n]].text != ""
+			b[ib[n]].diff = b[ib[n]].text != ""
+		}
+	}
+}
+
+func styledIndexes(segs []segment) []int {
+	var idx []int
+	for i, s := range segs {
+		if s.style != "" {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// truncateSegments shortens segments to width characters by dropping whole
+// steps of the module path from the left, which is the least specific
+// part of an address. The resource, or the last module step of a module
+// address, is never dropped, so the result may still exceed width.
+func truncateSegments(segs []segment, width int) []segment {
+	total := 0
+	for _, s := range segs {
+		total += utf8.RuneCountInString(s.text)
+	}
+	if width <= 0 || total <= width {
+		return segs
+	}
+	modules, resource := splitResource(segs)
+	droppable := len(modules)
+	if resource == nil {
+		droppable = len(modules) - 3
+	}
+	const ellipsis = "…"
+	total++
+	for droppable > 0 && total > width {
+		// Drop a whole module step, along with the separator following it.
+		for done := false; !done; droppable-- {
+			done = segs[0].text == "." && segs[0].style == ""
+			total -= utf8.RuneCountInString(segs[0].text)
+			segs = segs[1:]
+		}
+	}
+	return append([]segment{{text: ellipsis}}, segs...)
+}
@@ -0,0 +1,82 @@
+package address
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	a := parseAll(t, `module.a[0].data.foo.bar["x"]`)[0]
+
+	r := &Renderer{}
+	require.Equal(t, a.String(), r.Render(a))
+
+	r.Color = true
+	require.Equal(t, "\x1b[34mmodule.\x1b[0m\x1b[34ma\x1b[0m\x1b[33m[0]\x1b[0m."+
+		"\x1b[2mdata.\x1b[0m\x1b[36mfoo\x1b[0m.\x1b[32mbar\x1b[0m\x1b[33m[\"x\"]\x1b[0m", r.Render(a))
+
+	r = &Renderer{Width: 16}
+	require.Equal(t, `…data.foo.bar["x"]`, r.Render(a))
+	r.Width = 1
+	require.Equal(t, `…data.foo.bar["x"]`, r.Render(a))
+}
+
+func TestRenderWidth(t *testing.T) {
+	var tests = []struct {
+		addr     string
+		width    int
+		expected string
+	}{
+		{`module.aaaaaaaaaa.module.bbbbbbbbb.aws_instance.web["x"]`, 0, `module.aaaaaaaaaa.module.bbbbbbbbb.aws_instance.web["x"]`},
+		{`module.aaaaaaaaaa.module.bbbbbbbbb.aws_instance.web["x"]`, 45, `…module.bbbbbbbbb.aws_instance.web["x"]`},
+		{`module.aaaaaaaaaa.module.bbbbbbbbb.aws_instance.web["x"]`, 20, `…aws_instance.web["x"]`},
+		{`module.aaaaaaaaaa.module.bbbbbbbbb[0]`, 20, `…module.bbbbbbbbb[0]`},
+		{`module.aaaaaaaaaa.module.bbbbbbbbb[0]`, 5, `…module.bbbbbbbbb[0]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			r := &Renderer{Width: tt.width}
+			require.Equal(t, tt.expected, r.Render(parseAll(t, tt.addr)[0]))
+		})
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	from := parseAll(t, `module.a.aws_instance.web["blue"]`)[0]
+	to := parseAll(t, `module.a.aws_instance.web["green"]`)[0]
+
+	r := &Renderer{}
+	require.Equal(t, `module.a.aws_instance.web["blue"]  →  module.a.aws_instance.web["green"]`+"\n"+
+		`                         ^^^^^^^^                              ^^^^^^^^^`, r.RenderDiff(from, to))
+
+	r.Color = true
+	out := r.RenderDiff(from, to)
+	require.Contains(t, out, "\x1b[33m\x1b[1;4;7m[\"blue\"]\x1b[0m")
+	require.Contains(t, out, "\x1b[33m\x1b[1;4;7m[\"green\"]\x1b[0m")
+	require.NotContains(t, out, "\x1b[36m\x1b[1;4;7m")
+
+	r = &Renderer{}
+	same := r.RenderDiff(from, from)
+	require.Equal(t, `module.a.aws_instance.web["blue"]  →  module.a.aws_instance.web["blue"]`, same)
+
+	moved := r.RenderDiff(parseAll(t, `aws_instance.web`)[0], parseAll(t, `module.m.aws_instance.web`)[0])
+	require.Equal(t, `aws_instance.web  →  module.m.aws_instance.web`+"\n"+
+		`                     ^^^^^^^^`, moved)
+}
+
+func TestNewRendererNoColor(t *testing.T) {
+	old, ok := os.LookupEnv("NO_COLOR")
+	defer func() {
+		if ok {
+			os.Setenv("NO_COLOR", old)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+	}()
+	os.Setenv("NO_COLOR", "1")
+	require.False(t, NewRenderer(0).Color)
+	os.Unsetenv("NO_COLOR")
+	require.True(t, NewRenderer(80).Color)
+}
@@ -0,0 +1,159 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// EventType is the `type` of a message in Terraform's machine-readable UI
+// (`-json`) output.
+type EventType string
+
+// Event types that concern a single resource instance.
+const (
+	EventPlannedChange     EventType = "planned_change"
+	EventResourceDrift     EventType = "resource_drift"
+	EventApplyStart        EventType = "apply_start"
+	EventApplyProgress     EventType = "apply_progress"
+	EventApplyComplete     EventType = "apply_complete"
+	EventApplyErrored      EventType = "apply_errored"
+	EventProvisionStart    EventType = "provision_start"
+	EventProvisionProgress EventType = "provision_progress"
+	EventProvisionComplete EventType = "provision_complete"
+	EventProvisionErrored  EventType = "provision_errored"
+	EventRefreshStart      EventType = "refresh_start"
+	EventRefreshComplete   EventType = "refresh_complete"
+)
+
+// Event is a message from Terraform's machine-readable UI that concerns a
+// single resource instance.
+type Event struct {
+	Type      EventType
+	Level     string
+	Message   string
+	Timestamp time.Time
+	// Address of the resource instance the event is about.
+	Address *Address
+	// Action is the planned or applied action, e.g. `create` or `delete`.
+	Action string
+	// Elapsed is the time spent so far, for events that report it.
+	Elapsed time.Duration
+	// IDKey and IDValue identify the remote object, for events that report
+	// it.
+	IDKey   string
+	IDValue string
+}
+
+type jsonEvent struct {
+	Level     string         `json:"@level"`
+	Message   string         `json:"@message"`
+	Timestamp time.Time      `json:"@timestamp"`
+	Type      EventType      `json:"type"`
+	Hook      *jsonEventHook `json:"hook"`
+	Change    *jsonEventHook `json:"change"`
+}
+
+type jsonEventHook struct {
+	Resource       *jsonEventResource `json:"resource"`
+	Action         string             `json:"action"`
+	ElapsedSeconds float64            `json:"elapsed_seconds"`
+	IDKey          string             `json:"id_key"`
+	IDValue        string             `json:"id_value"`
+}
+
+type jsonEventResource struct {
+	Addr         string          `json:"addr"`
+	Module       string          `json:"module"`
+	ResourceType string          `json:"resource_type"`
+	ResourceName string          `json:"resource_name"`
+	ResourceKey  json.RawMessage `json:"resource_key"`
+}
+
+// EventDecoder reads resource events from a stream of Terraform's
+// machine-readable UI output, as produced by `terraform apply -json`.
+type EventDecoder struct {
+	dec *json.Decoder
+}
+
+// NewEventDecoder returns a decoder that reads UI messages from r.
+func NewEventDecoder(r io.Reader) *EventDecoder {
+	return &EventDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next event that concerns a resource instance. Messages
+// about anything else, such as the version header or the change summary, are
+// skipped. Returns io.EOF at the end of the stream.
+//
+// Each event carries the resource both as an address string and as
+// structured fields; an error is returned if the two disagree.
+func (d *EventDecoder) Next() (*Event, error) {
+	for {
+		var msg jsonEvent
+		if err := d.dec.Decode(&msg); err != nil {
+			return nil, err
+		}
+		hook := msg.Hook
+		if hook == nil {
+			hook = msg.Change
+		}
+		if hook == nil || hook.Resource == nil {
+			continue
+		}
+		addr, err := hook.Resource.address()
+		if err != nil {
+			return nil, fmt.Errorf("%s event: %w", msg.Type, err)
+		}
+		return &Event{
+			Type:      msg.Type,
+			Level:     msg.Level,
+			Message:   msg.Message,
+			Timestamp: msg.Timestamp,
+			Address:   addr,
+			Action:    hook.Action,
+			Elapsed:   time.Duration(hook.ElapsedSeconds * float64(time.Second)),
+			IDKey:     hook.IDKey,
+			IDValue:   hook.IDValue,
+		}, nil
+	}
+}
+
+// address parses `addr` and checks it against the structured fields.
+func (r *jsonEventResource) address() (*Address, error) {
+	a, err := NewAddress(r.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if got := a.ModulePath.String(); got != r.Module {
+		return nil, fmt.Errorf("address %q is not in module %q", r.Addr, r.Module)
+	}
+	if a.ResourceSpec.Type != r.ResourceType || a.ResourceSpec.Name != r.ResourceName {
+		return nil, fmt.Errorf("address %q is not resource %s.%s", r.Addr, r.ResourceType, r.ResourceName)
+	}
+	key, err := r.key()
+	if err != nil {
+		return nil, err
+	}
+	if a.ResourceSpec.Index.Value != key.Value {
+		return nil, fmt.Errorf("address %q does not have key %s", r.Addr, r.ResourceKey)
+	}
+	return a, nil
+}
+
+// key decodes `resource_key`, which is either null, a number or a string.
+func (r *jsonEventResource) key() (Index, error) {
+	if len(r.ResourceKey) == 0 || string(r.ResourceKey) == "null" {
+		return Index{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(r.ResourceKey, &s); err == nil {
+		return Index{Value: s}, nil
+	}
+	n, err := strconv.Atoi(string(r.ResourceKey))
+	if err != nil {
+		return Index{}, fmt.Errorf("invalid resource key %s", r.ResourceKey)
+	}
+	return Index{Value: n}, nil
+}
@@ -0,0 +1,63 @@
+package address
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const applyStream = `{"@level":"info","@message":"Terraform 1.9.0","@module":"terraform.ui","@timestamp":"2024-06-01T10:00:00.000000Z","terraform":"1.9.0","type":"version","ui":"1.2"}
+{"@level":"info","@message":"module.a[0].aws_instance.b[\"x\"]: Plan to create","@module":"terraform.ui","@timestamp":"2024-06-01T10:00:01.000000Z","change":{"resource":{"addr":"module.a[0].aws_instance.b[\"x\"]","module":"module.a[0]","resource":"aws_instance.b[\"x\"]","implied_provider":"aws","resource_type":"aws_instance","resource_name":"b","resource_key":"x"},"action":"create"},"type":"planned_change"}
+{"@level":"info","@message":"aws_instance.c[1]: Destroying...","@module":"terraform.ui","@timestamp":"2024-06-01T10:00:02.000000Z","hook":{"resource":{"addr":"aws_instance.c[1]","module":"","resource":"aws_instance.c[1]","implied_provider":"aws","resource_type":"aws_instance","resource_name":"c","resource_key":1},"action":"delete","id_key":"id","id_value":"i-123"},"type":"apply_start"}
+{"@level":"info","@message":"aws_instance.c[1]: Destruction complete after 3s","@module":"terraform.ui","@timestamp":"2024-06-01T10:00:05.000000Z","hook":{"resource":{"addr":"aws_instance.c[1]","module":"","resource":"aws_instance.c[1]","implied_provider":"aws","resource_type":"aws_instance","resource_name":"c","resource_key":1},"action":"delete","elapsed_seconds":3},"type":"apply_complete"}
+{"@level":"info","@message":"Apply complete! Resources: 0 added, 0 changed, 1 destroyed.","@module":"terraform.ui","@timestamp":"2024-06-01T10:00:05.000000Z","changes":{"add":0,"change":0,"remove":1,"operation":"apply"},"type":"change_summary"}
+`
+
+func TestEventDecoder(t *testing.T) {
+	d := NewEventDecoder(strings.NewReader(applyStream))
+
+	e, err := d.Next()
+	require.NoError(t, err)
+	require.Equal(t, EventPlannedChange, e.Type)
+	require.Equal(t, `module.a[0].aws_instance.b["x"]`, e.Address.String())
+	require.Equal(t, "create", e.Action)
+
+	e, err = d.Next()
+	require.NoError(t, err)
+	require.Equal(t, EventApplyStart, e.Type)
+	require.Equal(t, `aws_instance.c[1]`, e.Address.String())
+	require.Equal(t, "i-123", e.IDValue)
+	require.Equal(t, time.Date(2024, 6, 1, 10, 0, 2, 0, time.UTC), e.Timestamp)
+
+	e, err = d.Next()
+	require.NoError(t, err)
+	require.Equal(t, EventApplyComplete, e.Type)
+	require.Equal(t, 3*time.Second, e.Elapsed)
+
+	_, err = d.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestEventDecoderMismatch(t *testing.T) {
+	var tests = []struct {
+		name     string
+		resource string
+	}{
+		{"module", `{"addr":"module.a.foo.bar","module":"module.b","resource_type":"foo","resource_name":"bar","resource_key":null}`},
+		{"type", `{"addr":"foo.bar","module":"","resource_type":"baz","resource_name":"bar","resource_key":null}`},
+		{"name", `{"addr":"foo.bar","module":"","resource_type":"foo","resource_name":"baz","resource_key":null}`},
+		{"key", `{"addr":"foo.bar[0]","module":"","resource_type":"foo","resource_name":"bar","resource_key":"0"}`},
+		{"missing key", `{"addr":"foo.bar[0]","module":"","resource_type":"foo","resource_name":"bar","resource_key":null}`},
+		{"invalid addr", `{"addr":"foo","module":"","resource_type":"foo","resource_name":"bar","resource_key":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := `{"type":"apply_start","hook":{"resource":` + tt.resource + `,"action":"create"}}`
+			_, err := NewEventDecoder(strings.NewReader(msg)).Next()
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package address
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// AppendText appends the string representation of the address to dst and
+// returns the extended buffer. It does not allocate if dst has enough
+// capacity, which makes it suitable for hot logging paths.
+func (a *Address) AppendText(dst []byte) []byte {
+	dst = a.appendInstance(dst)
+	if a.DeposedKey != "" {
+		dst = append(dst, " (deposed object "...)
+		dst = append(dst, a.DeposedKey...)
+		dst = append(dst, ')')
+	}
+	return dst
+}
+
+func (a *Address) appendInstance(dst []byte) []byte {
+	dst = a.ModulePath.appendText(dst)
+	if a.IsModule() {
+		return dst
+	}
+	if len(a.ModulePath) > 0 {
+		dst = append(dst, '.')
+	}
+	return a.ResourceSpec.appendText(dst)
+}
+
+func (m ModulePath) appendText(dst []byte) []byte {
+	for i := range m {
+		if i > 0 {
+			dst = append(dst, '.')
+		}
+		dst = m[i].appendText(dst)
+	}
+	return dst
+}
+
+func (m *Module) appendText(dst []byte) []byte {
+	dst = append(dst, "module."...)
+	dst = append(dst, m.Name...)
+	return m.Index.appendBracketed(dst)
+}
+
+func (r *ResourceSpec) appendText(dst []byte) []byte {
+	if r.Mode == DataResourceMode {
+		dst = append(dst, "data."...)
+	}
+	dst = append(dst, r.Type...)
+	dst = append(dst, '.')
+	dst = append(dst, r.Name...)
+	return r.Index.appendBracketed(dst)
+}
+
+// appendBracketed appends the index in brackets, if there is one.
+func (i *Index) appendBracketed(dst []byte) []byte {
+	if i.Value == nil {
+		return dst
+	}
+	dst = append(dst, '[')
+	dst = i.appendText(dst)
+	return append(dst, ']')
+}
+
+func (i *Index) appendText(dst []byte) []byte {
+	switch v := i.Value.(type) {
+	case nil:
+		return dst
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case string:
+		return strconv.AppendQuote(dst, v)
+	case UnknownKey:
+		return append(dst, '*')
+	default:
+		panic(fmt.Errorf("got unknown type %T", v))
+	}
+}
+
+// HCL returns the address as a traversal in Terraform's native syntax, as
+// used in `moved` and `import` blocks. It differs from String in quoting
+// string keys as HCL rather than Go string literals, and in omitting any
+// deposed object suffix.
+func (a *Address) HCL() string {
+	var b strings.Builder
+	for i, m := range a.ModulePath {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString("module.")
+		b.WriteString(m.Name)
+		b.WriteString(hclIndex(m.Index))
+	}
+	if a.IsModule() {
+		return b.String()
+	}
+	if len(a.ModulePath) > 0 {
+		b.WriteByte('.')
+	}
+	r := a.ResourceSpec
+	if r.Mode == DataResourceMode {
+		b.WriteString("data.")
+	}
+	b.WriteString(r.Type)
+	b.WriteByte('.')
+	b.WriteString(r.Name)
+	b.WriteString(hclIndex(r.Index))
+	return b.String()
+}
+
+func hclIndex(i Index) string {
+	switch v := i.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return "[" + hclString(v) + "]"
+	case int:
+		return "[" + strconv.Itoa(v) + "]"
+	default:
+		return "[" + i.String() + "]"
+	}
+}
+
+// Format implements fmt.Formatter. The verbs are:
+//
+//	%s, %v  the string representation, honoring width and the `-` flag
+//	%q      the string representation, quoted
+//	%+v     each component labeled, e.g. `module=module.a[0] type=foo name=bar key=1`
+//	%#v     Go syntax that constructs the address
+func (a *Address) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, a.goSyntax())
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprint(f, a.labeled())
+	case verb == 's' || verb == 'v' || verb == 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), a.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(*address.Address=%s)", verb, a.String())
+	}
+}
+
+// labeled returns the components of the address as space-separated
+// `label=value` pairs, using the same labels as LogValue.
+func (a *Address) labeled() string {
+	var b []byte
+	for _, attr := range a.logAttrs() {
+		if len(b) > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, attr.Key...)
+		b = append(b, '=')
+		b = append(b, attr.Value.String()...)
+	}
+	return string(b)
+}
+
+func (a *Address) goSyntax() string {
+	b := []byte("&address.Address{")
+	sep := false
+	field := func(name string) {
+		if sep {
+			b = append(b, ", "...)
+		}
+		sep = true
+		b = append(b, name...)
+		b = append(b, ':')
+	}
+	if len(a.ModulePath) > 0 {
+		field("ModulePath")
+		b = append(b, "address.ModulePath{"...)
+		for i, m := range a.ModulePath {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = append(b, "address.Module{Name:"...)
+			b = strconv.AppendQuote(b, m.Name)
+			if m.Index.Value != nil {
+				b = append(b, ", Index:"...)
+				b = m.Index.appendGoSyntax(b)
+			}
+			b = append(b, '}')
+		}
+		b = append(b, '}')
+	}
+	if !a.IsModule() {
+		r := a.ResourceSpec
+		field("ResourceSpec")
+		b = append(b, "address.ResourceSpec{"...)
+		if r.Mode == DataResourceMode {
+			b = append(b, "Mode:address.DataResourceMode, "...)
+		}
+		b = append(b, "Type:"...)
+		b = strconv.AppendQuote(b, r.Type)
+		b = append(b, ", Name:"...)
+		b = strconv.AppendQuote(b, r.Name)
+		if r.Index.Value != nil {
+			b = append(b, ", Index:"...)
+			b = r.Index.appendGoSyntax(b)
+		}
+		b = append(b, '}')
+	}
+	if a.DeposedKey != "" {
+		field("DeposedKey")
+		b = strconv.AppendQuote(b, a.DeposedKey)
+	}
+	return string(append(b, '}'))
+}
+
+func (i *Index) appendGoSyntax(dst []byte) []byte {
+	dst = append(dst, "address.Index{Value:"...)
+	if i.IsUnknown() {
+		dst = append(dst, "address.UnknownKey{}"...)
+	} else {
+		dst = i.appendText(dst)
+	}
+	return append(dst, '}')
+}
+
+// LogValue implements slog.LogValuer, logging the components of the address
+// as a group with the fields `module`, `type`, `name` and, when present,
+// `mode`, `key` and `deposed`.
+func (a *Address) LogValue() slog.Value {
+	return slog.GroupValue(a.logAttrs()...)
+}
+
+func (a *Address) logAttrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 6)
+	attrs = append(attrs, slog.String("module", a.ModulePath.String()))
+	if a.IsModule() {
+		return attrs
+	}
+	r := a.ResourceSpec
+	if r.Mode == DataResourceMode {
+		attrs = append(attrs, slog.String("mode", r.Mode.String()))
+	}
+	attrs = append(attrs, slog.String("type", r.Type), slog.String("name", r.Name))
+	switch v := r.Index.Value.(type) {
+	case nil:
+	case int:
+		attrs = append(attrs, slog.Int("key", v))
+	case string:
+		attrs = append(attrs, slog.String("key", v))
+	default:
+		attrs = append(attrs, slog.String("key", r.Index.String()))
+	}
+	if a.DeposedKey != "" {
+		attrs = append(attrs, slog.String("deposed", a.DeposedKey))
+	}
+	return attrs
+}
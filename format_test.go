@@ -0,0 +1,65 @@
+package address
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	a := parseAll(t, `module.a[0].data.foo.bar["x"] (deposed object 1a2b3c4d)`)[0]
+	var tests = []struct {
+		format   string
+		expected string
+	}{
+		{"%s", `module.a[0].data.foo.bar["x"] (deposed object 1a2b3c4d)`},
+		{"%v", `module.a[0].data.foo.bar["x"] (deposed object 1a2b3c4d)`},
+		{"%q", `"module.a[0].data.foo.bar[\"x\"] (deposed object 1a2b3c4d)"`},
+		{"%+v", `module=module.a[0] mode=data type=foo name=bar key=x deposed=1a2b3c4d`},
+		{"%#v", `&address.Address{ModulePath:address.ModulePath{address.Module{Name:"a", Index:address.Index{Value:0}}}, ResourceSpec:address.ResourceSpec{Mode:address.DataResourceMode, Type:"foo", Name:"bar", Index:address.Index{Value:"x"}}, DeposedKey:"1a2b3c4d"}`},
+		{"%d", `%!d(*address.Address=module.a[0].data.foo.bar["x"] (deposed object 1a2b3c4d))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			require.Equal(t, tt.expected, fmt.Sprintf(tt.format, a))
+		})
+	}
+
+	b := parseAll(t, `foo.bar[*]`)[0]
+	require.Equal(t, "foo.bar[*]  |", fmt.Sprintf("%-12s|", b))
+	require.Equal(t, "   foo.bar[*]|", fmt.Sprintf("%13v|", b))
+	require.Equal(t, `&address.Address{ResourceSpec:address.ResourceSpec{Type:"foo", Name:"bar", Index:address.Index{Value:address.UnknownKey{}}}}`, fmt.Sprintf("%#v", b))
+	require.Equal(t, `module= type=foo name=bar key=*`, fmt.Sprintf("%+v", b))
+
+	m := &Address{ModulePath: ModulePath{{Name: "a"}}}
+	require.Equal(t, `&address.Address{ModulePath:address.ModulePath{address.Module{Name:"a"}}}`, fmt.Sprintf("%#v", m))
+}
+
+func TestAppendText(t *testing.T) {
+	a := parseAll(t, `module.a[0].module.b["x"].foo.bar[12]`)[0]
+	buf := make([]byte, 0, 64)
+	require.Equal(t, a.String(), string(a.AppendText(buf)))
+	require.Equal(t, "> "+a.String(), string(a.AppendText([]byte("> "))))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = a.AppendText(buf[:0])
+	})
+	require.Zero(t, allocs)
+}
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("applying", "addr", parseAll(t, `module.a.foo.bar[1]`)[0])
+	require.Equal(t, "level=INFO msg=applying addr.module=module.a addr.type=foo addr.name=bar addr.key=1\n", buf.String())
+}
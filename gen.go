@@ -1,3 +1,3 @@
 package address
 
-//go:generate pigeon -o address.go address.peg
+//go:generate pigeon -alternate-entrypoints ModuleAddress,ProviderConfig,RunReference -o address.go address.peg
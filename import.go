@@ -0,0 +1,227 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportForEach is an `import` block repeated with `for_each`, as in
+//
+//	import {
+//	  for_each = var.buckets
+//	  to       = aws_s3_bucket.b[each.key]
+//	  id       = "bucket-${each.value}"
+//	}
+//
+// The `to` address may use `each.key` and `each.value` as instance keys of
+// its module steps and resource. The `id` is either `each.key`,
+// `each.value` or a quoted string interpolating them.
+type ImportForEach struct {
+	to *AddressTemplate
+	id []importIDPart
+}
+
+// importIDPart is a literal part of an import ID, or `each.key` or
+// `each.value` when each is set.
+type importIDPart struct {
+	literal string
+	each    string
+}
+
+// ImportTarget is a resource instance to import.
+type ImportTarget struct {
+	// Key is the for_each key the target was expanded for.
+	Key string
+	To  *Address
+	ID  string
+}
+
+// ParseImportForEach parses the `to` and `id` expressions of an import block
+// with `for_each`. Returns an error if either is malformed.
+func ParseImportForEach(to, id string) (*ImportForEach, error) {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(to); i++ {
+		c := to[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(to):
+			b.WriteString(to[i : i+2])
+			i++
+			continue
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '$':
+			return nil, fmt.Errorf("invalid import address %q: unexpected $", to)
+		case strings.HasPrefix(to[i:], "[each.key]"):
+			b.WriteString("[$key]")
+			i += len("[each.key]") - 1
+			continue
+		case strings.HasPrefix(to[i:], "[each.value]"):
+			b.WriteString("[$value]")
+			i += len("[each.value]") - 1
+			continue
+		case strings.HasPrefix(to[i:], "each.") && i > 0 && to[i-1] == '[':
+			// Outside brackets, each may name a module or resource.
+			return nil, fmt.Errorf("invalid import address %q: each may only be used as an instance key", to)
+		}
+		b.WriteByte(c)
+	}
+	t, err := ParseAddressTemplate(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid import address %q: %w", to, err)
+	}
+	a := t.addr
+	switch {
+	case a.IsModule() || a.ResourceSpec.Mode != ManagedResourceMode || a.ResourceSpec.Type == "module":
+		return nil, fmt.Errorf("invalid import address %q: must be a managed resource", to)
+	case a.DeposedKey != "":
+		return nil, fmt.Errorf("invalid import address %q: cannot import a deposed object", to)
+	}
+
+	parts, err := parseImportID(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import ID %s: %w", id, err)
+	}
+	return &ImportForEach{to: t, id: parts}, nil
+}
+
+// parseImportID parses an import ID expression.
+func parseImportID(id string) ([]importIDPart, error) {
+	switch id {
+	case "each.key":
+		return []importIDPart{{each: "key"}}, nil
+	case "each.value":
+		return []importIDPart{{each: "value"}}, nil
+	}
+	if len(id) < 2 || id[0] != '"' || id[len(id)-1] != '"' {
+		return nil, errors.New("must be each.key, each.value or a quoted string")
+	}
+	var parts []importIDPart
+	var literal strings.Builder
+	flush := func() error {
+		if literal.Len() == 0 {
+			return nil
+		}
+		s, err := strconv.Unquote(`"` + literal.String() + `"`)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, importIDPart{literal: s})
+		literal.Reset()
+		return nil
+	}
+	s := id[1 : len(id)-1]
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			literal.WriteString(s[i : i+2])
+			i++
+		case strings.HasPrefix(s[i:], "$${"):
+			literal.WriteString("${")
+			i += 2
+		case strings.HasPrefix(s[i:], "%%{"):
+			literal.WriteString("%{")
+			i += 2
+		case strings.HasPrefix(s[i:], "%{"):
+			return nil, errors.New("template directives are not supported")
+		case strings.HasPrefix(s[i:], "${"):
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, errors.New("unterminated interpolation")
+			}
+			expr := strings.TrimSpace(s[i+2 : i+end])
+			if expr != "each.key" && expr != "each.value" {
+				return nil, fmt.Errorf("unsupported interpolation of %s", expr)
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			parts = append(parts, importIDPart{each: strings.TrimPrefix(expr, "each.")})
+			i += end
+		default:
+			literal.WriteByte(s[i])
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// Expand returns the target of each element of the for_each collection
+// each, sorted by key. Use CheckImports to detect conflicting targets.
+func (im *ImportForEach) Expand(each map[string]string) ([]ImportTarget, error) {
+	keys := make([]string, 0, len(each))
+	for k := range each {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	targets := make([]ImportTarget, 0, len(keys))
+	for _, k := range keys {
+		v := each[k]
+		to, err := im.to.Bind(map[string]Index{"key": {Value: k}, "value": {Value: v}})
+		if err != nil {
+			return nil, err
+		}
+		var id strings.Builder
+		for _, p := range im.id {
+			switch p.each {
+			case "key":
+				id.WriteString(k)
+			case "value":
+				id.WriteString(v)
+			default:
+				id.WriteString(p.literal)
+			}
+		}
+		targets = append(targets, ImportTarget{Key: k, To: to, ID: id.String()})
+	}
+	return targets, nil
+}
+
+// ImportConflictError is returned when an import target is imported twice,
+// or is already in state.
+type ImportConflictError struct {
+	Target ImportTarget
+	// Other is the other import of the same target, unless the target is
+	// in state.
+	Other *ImportTarget
+}
+
+func (e *ImportConflictError) Error() string {
+	if e.Other == nil {
+		return fmt.Sprintf("%s is already in state", e.Target.To)
+	}
+	return fmt.Sprintf("%s is imported twice, with IDs %q and %q", e.Target.To, e.Other.ID, e.Target.ID)
+}
+
+// CheckImports returns an *ImportConflictError for each target of targets
+// that is imported more than once, or that is already among the addresses in
+// state, joined with errors.Join. Returns nil if there is no conflict.
+func CheckImports(targets []ImportTarget, state []*Address) error {
+	inState := make(map[string]bool, len(state))
+	for _, a := range state {
+		if a.DeposedKey == "" {
+			inState[a.String()] = true
+		}
+	}
+	seen := make(map[string]int, len(targets))
+	var errs []error
+	for i, t := range targets {
+		key := t.To.String()
+		if inState[key] {
+			errs = append(errs, &ImportConflictError{Target: t})
+		}
+		if j, ok := seen[key]; ok {
+			errs = append(errs, &ImportConflictError{Target: t, Other: &targets[j]})
+			continue
+		}
+		seen[key] = i
+	}
+	return errors.Join(errs...)
+}
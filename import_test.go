@@ -0,0 +1,109 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportForEachExpand(t *testing.T) {
+	each := map[string]string{"logs": "acme-logs", "assets": "acme-assets"}
+	var tests = []struct {
+		to       string
+		id       string
+		expected []ImportTarget
+	}{
+		{
+			`aws_s3_bucket.b[each.key]`,
+			`each.value`,
+			[]ImportTarget{
+				{Key: "assets", To: parseAll(t, `aws_s3_bucket.b["assets"]`)[0], ID: "acme-assets"},
+				{Key: "logs", To: parseAll(t, `aws_s3_bucket.b["logs"]`)[0], ID: "acme-logs"},
+			},
+		},
+		{
+			`module.store[each.key].aws_s3_bucket.beach[each.value]`,
+			`"arn:aws:s3:::${each.value}/${ each.key }\t$${literal}%%{literal}"`,
+			[]ImportTarget{
+				{Key: "assets", To: parseAll(t, `module.store["assets"].aws_s3_bucket.beach["acme-assets"]`)[0], ID: "arn:aws:s3:::acme-assets/assets\t${literal}%{literal}"},
+				{Key: "logs", To: parseAll(t, `module.store["logs"].aws_s3_bucket.beach["acme-logs"]`)[0], ID: "arn:aws:s3:::acme-logs/logs\t${literal}%{literal}"},
+			},
+		},
+		{
+			`module.each.each.b[each.key]`,
+			`each.key`,
+			[]ImportTarget{
+				{Key: "assets", To: parseAll(t, `module.each.each.b["assets"]`)[0], ID: "assets"},
+				{Key: "logs", To: parseAll(t, `module.each.each.b["logs"]`)[0], ID: "logs"},
+			},
+		},
+		{
+			`aws_s3_bucket.b["each.key"]`,
+			`each.key`,
+			[]ImportTarget{
+				{Key: "assets", To: parseAll(t, `aws_s3_bucket.b["each.key"]`)[0], ID: "assets"},
+				{Key: "logs", To: parseAll(t, `aws_s3_bucket.b["each.key"]`)[0], ID: "logs"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			im, err := ParseImportForEach(tt.to, tt.id)
+			require.NoError(t, err)
+			targets, err := im.Expand(each)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, targets)
+		})
+	}
+}
+
+func TestParseImportForEachInvalid(t *testing.T) {
+	var tests = []struct {
+		to string
+		id string
+	}{
+		{`aws_s3_bucket.each.key`, `each.key`},
+		{`aws_s3_bucket.b[each.key.x]`, `each.key`},
+		{`aws_s3_bucket.b[$key]`, `each.key`},
+		{`data.aws_s3_bucket.b[each.key]`, `each.key`},
+		{`module.a[each.key]`, `each.key`},
+		{`aws_s3_bucket.b[each.key] (deposed object 00000001)`, `each.key`},
+		{`aws_s3_bucket.b[each.key]`, `each.other`},
+		{`aws_s3_bucket.b[each.key]`, `"${var.prefix}"`},
+		{`aws_s3_bucket.b[each.key]`, `"${each.key"`},
+		{`aws_s3_bucket.b[each.key]`, `"\q"`},
+		{`aws_s3_bucket.b[each.key]`, `"%{if each.key == "a"}a%{endif}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to+" "+tt.id, func(t *testing.T) {
+			_, err := ParseImportForEach(tt.to, tt.id)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCheckImports(t *testing.T) {
+	im, err := ParseImportForEach(`aws_s3_bucket.b[each.key]`, `each.value`)
+	require.NoError(t, err)
+	targets, err := im.Expand(map[string]string{"a": "id-a", "b": "id-b"})
+	require.NoError(t, err)
+
+	require.NoError(t, CheckImports(targets, parseAll(t, `aws_s3_bucket.b`, `aws_s3_bucket.b["b"] (deposed object 00000001)`)))
+
+	err = CheckImports(targets, parseAll(t, `aws_s3_bucket.b["b"]`))
+	require.EqualError(t, err, `aws_s3_bucket.b["b"] is already in state`)
+
+	single, err := ParseImportForEach(`aws_s3_bucket.b["a"]`, `"fixed"`)
+	require.NoError(t, err)
+	more, err := single.Expand(map[string]string{"x": "", "y": ""})
+	require.NoError(t, err)
+	err = CheckImports(append(targets, more...), nil)
+	require.EqualError(t, err, `aws_s3_bucket.b["a"] is imported twice, with IDs "id-a" and "fixed"
+aws_s3_bucket.b["a"] is imported twice, with IDs "id-a" and "fixed"`)
+
+	var conflict *ImportConflictError
+	require.True(t, errors.As(err, &conflict))
+	require.Equal(t, "x", conflict.Target.Key)
+	require.Equal(t, "a", conflict.Other.Key)
+}
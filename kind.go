@@ -0,0 +1,66 @@
+package address
+
+import "strings"
+
+// Provider returns the local name of the provider of the resource type, the
+// prefix of the type before its first underscore as in Terraform, e.g. `aws`
+// for `aws_s3_bucket`.
+//
+// Provider names that themselves contain underscores or dashes can be given
+// in known, in which case the longest known provider prefixing the type
+// wins: with known `google_beta`, `google_beta_instance` is an instance of
+// `google_beta` rather than a `beta_instance` of `google`.
+func (r ResourceSpec) Provider(known ...string) string {
+	p, _ := r.split(known)
+	return p
+}
+
+// Kind returns the resource type without its provider prefix, e.g.
+// `s3_bucket` for `aws_s3_bucket`. See Provider for known. The kind is empty
+// for a type that is only a provider name.
+func (r ResourceSpec) Kind(known ...string) string {
+	_, k := r.split(known)
+	return k
+}
+
+func (r ResourceSpec) split(known []string) (string, string) {
+	best := ""
+	for _, p := range known {
+		if len(p) > len(best) && (r.Type == p || strings.HasPrefix(r.Type, p+"_")) {
+			best = p
+		}
+	}
+	if best != "" {
+		return best, strings.TrimPrefix(r.Type[len(best):], "_")
+	}
+	p, k, _ := strings.Cut(r.Type, "_")
+	return p, k
+}
+
+// CountByProvider counts the addresses in addrs by the provider of their
+// resource type. Each address is counted, so that a set of instances counts
+// every instance. Module addresses are skipped. See Provider for known.
+func CountByProvider(addrs []*Address, known ...string) map[string]int {
+	return countBy(addrs, func(r ResourceSpec) string {
+		return r.Provider(known...)
+	})
+}
+
+// CountByKind counts the addresses in addrs by the kind of their resource
+// type, as CountByProvider does by provider.
+func CountByKind(addrs []*Address, known ...string) map[string]int {
+	return countBy(addrs, func(r ResourceSpec) string {
+		return r.Kind(known...)
+	})
+}
+
+func countBy(addrs []*Address, key func(ResourceSpec) string) map[string]int {
+	counts := make(map[string]int)
+	for _, a := range addrs {
+		if a.IsModule() {
+			continue
+		}
+		counts[key(a.ResourceSpec)]++
+	}
+	return counts
+}
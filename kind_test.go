@@ -0,0 +1,50 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceSpecProviderKind(t *testing.T) {
+	known := []string{"google", "google_beta", "my-cloud"}
+	var tests = []struct {
+		typ      string
+		provider string
+		kind     string
+	}{
+		{"aws_s3_bucket", "aws", "s3_bucket"},
+		{"google_compute_instance", "google", "compute_instance"},
+		{"google_beta_instance", "google_beta", "instance"},
+		{"google_betamax", "google", "betamax"},
+		{"my-cloud_vm", "my-cloud", "vm"},
+		{"other-cloud_vm", "other-cloud", "vm"},
+		{"google_beta", "google_beta", ""},
+		{"null", "null", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			r := ResourceSpec{Type: tt.typ, Name: "x"}
+			require.Equal(t, tt.provider, r.Provider(known...))
+			require.Equal(t, tt.kind, r.Kind(known...))
+		})
+	}
+
+	r := ResourceSpec{Type: "google_beta_instance"}
+	require.Equal(t, "google", r.Provider())
+	require.Equal(t, "beta_instance", r.Kind())
+}
+
+func TestCountBy(t *testing.T) {
+	addrs := parseAll(t,
+		`aws_instance.web[0]`,
+		`aws_instance.web[1]`,
+		`module.a.aws_s3_bucket.logs`,
+		`data.google_compute_instance.x`,
+		`google_beta_compute_instance.y`,
+	)
+	addrs = append(addrs, &Address{ModulePath: ModulePath{{Name: "a"}}})
+
+	require.Equal(t, map[string]int{"aws": 3, "google": 1, "google_beta": 1}, CountByProvider(addrs, "google_beta"))
+	require.Equal(t, map[string]int{"instance": 2, "s3_bucket": 1, "compute_instance": 2}, CountByKind(addrs, "google_beta"))
+}
@@ -0,0 +1,168 @@
+package address
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// LockConflictError is returned when a target cannot be locked because it
+// overlaps a target that is already locked.
+type LockConflictError struct {
+	Target *Address
+	Held   *Address
+}
+
+func (e *LockConflictError) Error() string {
+	return fmt.Sprintf("%s conflicts with locked target %s", e.Target, e.Held)
+}
+
+// LockManager hands out locks over sets of targets, so that operations on
+// overlapping parts of a configuration do not run concurrently. Two targets
+// overlap when some resource instance is selected by both: locking a module
+// therefore excludes every resource within it, locking a resource excludes
+// each of its instances, and an unknown instance key excludes every key.
+//
+// A set of targets is locked all at once or not at all, so callers can never
+// deadlock by holding part of a set while waiting for the rest.
+type LockManager struct {
+	mu     sync.Mutex
+	held   map[*Lease]struct{}
+	notify chan struct{}
+}
+
+// Lease is a set of locked targets.
+type Lease struct {
+	m       *LockManager
+	targets []*Address
+}
+
+// NewLockManager returns a lock manager with no targets locked.
+func NewLockManager() *LockManager {
+	return &LockManager{
+		held:   make(map[*Lease]struct{}),
+		notify: make(chan struct{}),
+	}
+}
+
+// TryAcquire locks targets if none of them overlaps a locked target, and
+// returns a *LockConflictError otherwise.
+func (m *LockManager) TryAcquire(targets ...*Address) (*Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.conflict(targets); err != nil {
+		return nil, err
+	}
+	return m.lease(targets), nil
+}
+
+// Acquire locks targets, waiting until none of them overlaps a locked target
+// or ctx is done.
+func (m *LockManager) Acquire(ctx context.Context, targets ...*Address) (*Lease, error) {
+	for {
+		m.mu.Lock()
+		if m.conflict(targets) == nil {
+			l := m.lease(targets)
+			m.mu.Unlock()
+			return l, nil
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Targets returns the targets held by the lease.
+func (l *Lease) Targets() []*Address {
+	targets := make([]*Address, len(l.targets))
+	for i, t := range l.targets {
+		targets[i] = t.Clone()
+	}
+	return targets
+}
+
+// Release unlocks the targets of the lease. Releasing a lease more than once
+// has no effect.
+func (l *Lease) Release() {
+	m := l.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.held[l]; !ok {
+		return
+	}
+	delete(m.held, l)
+	close(m.notify)
+	m.notify = make(chan struct{})
+}
+
+// conflict returns an error for the first target that overlaps a locked
+// target. m.mu must be held.
+func (m *LockManager) conflict(targets []*Address) error {
+	for l := range m.held {
+		for _, held := range l.targets {
+			for _, t := range targets {
+				if overlaps(held, t) {
+					return &LockConflictError{Target: t.Clone(), Held: held.Clone()}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lease records targets as locked. m.mu must be held.
+func (m *LockManager) lease(targets []*Address) *Lease {
+	l := &Lease{m: m, targets: make([]*Address, len(targets))}
+	for i, t := range targets {
+		l.targets[i] = t.Clone()
+	}
+	m.held[l] = struct{}{}
+	return l
+}
+
+// overlaps reports whether some resource instance may be selected by both a
+// and b. Module steps are compared up to the shorter module path: an unknown
+// key matches any key, and the final step of a module address without an
+// index matches every instance of the module, whose whole subtree it covers.
+// A resource without an index matches every instance.
+func overlaps(a, b *Address) bool {
+	n := len(a.ModulePath)
+	if len(b.ModulePath) < n {
+		n = len(b.ModulePath)
+	}
+	for i := 0; i < n; i++ {
+		x, y := a.ModulePath[i], b.ModulePath[i]
+		all := (a.IsModule() && i == len(a.ModulePath)-1) || (b.IsModule() && i == len(b.ModulePath)-1)
+		if x.Name != y.Name || !keysOverlap(x.Index, y.Index, all) {
+			return false
+		}
+	}
+	switch {
+	case a.IsModule() && len(a.ModulePath) <= len(b.ModulePath),
+		b.IsModule() && len(b.ModulePath) <= len(a.ModulePath):
+		return true
+	case a.IsModule() || b.IsModule() || len(a.ModulePath) != len(b.ModulePath):
+		return false
+	}
+	ra, rb := a.ResourceSpec, b.ResourceSpec
+	if ra.Mode != rb.Mode || ra.Type != rb.Type || ra.Name != rb.Name || !keysOverlap(ra.Index, rb.Index, true) {
+		return false
+	}
+	return a.DeposedKey == "" || b.DeposedKey == "" || a.DeposedKey == b.DeposedKey
+}
+
+// keysOverlap reports whether the instance keys x and y may select the same
+// instance. With all set, an absent key selects every instance.
+func keysOverlap(x, y Index, all bool) bool {
+	if all && (x.Value == nil || y.Value == nil) {
+		return true
+	}
+	return x.contains(y) || y.contains(x)
+}
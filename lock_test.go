@@ -0,0 +1,119 @@
+package address
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockConflicts(t *testing.T) {
+	var tests = []struct {
+		held     *Address
+		target   string
+		conflict bool
+	}{
+		{parseAll(t, `foo.bar`)[0], `foo.bar[0]`, true},
+		{parseAll(t, `foo.bar[0]`)[0], `foo.bar`, true},
+		{parseAll(t, `foo.bar[0]`)[0], `foo.bar[1]`, false},
+		{parseAll(t, `foo.bar[*]`)[0], `foo.bar["a"]`, true},
+		{parseAll(t, `foo.bar`)[0], `foo.baz`, false},
+		{&Address{ModulePath: ModulePath{{Name: "a"}}}, `module.a[0].module.b.foo.bar`, true},
+		{&Address{ModulePath: ModulePath{{Name: "a", Index: Index{0}}}}, `module.a[1].foo.bar`, false},
+		{&Address{ModulePath: ModulePath{{Name: "a", Index: Index{0}}}}, `module.a[0].foo.bar`, true},
+		{parseAll(t, `module.a[0].foo.bar`)[0], `module.a.foo.bar`, false},
+		{&Address{}, `module.a.foo.bar`, true},
+		{parseAll(t, `module.a[*].foo.bar[0]`)[0], `module.a[0].foo.bar`, true},
+		{parseAll(t, `module.a[0].foo.bar`)[0], `module.a[*].foo.bar[0]`, true},
+		{parseAll(t, `module.a[*].foo.bar[0]`)[0], `module.a[0].foo.bar[1]`, false},
+		{parseAll(t, `module.a[*].foo.bar`)[0], `module.a.foo.bar`, false},
+		{&Address{ModulePath: ModulePath{{Name: "a", Index: Index{UnknownKey{}}}}}, `module.a[0].module.b.foo.bar`, true},
+		{&Address{ModulePath: ModulePath{{Name: "a"}, {Name: "b", Index: Index{0}}}}, `module.a.module.b[*].foo.bar`, true},
+		{parseAll(t, `foo.bar[0] (deposed object 00000001)`)[0], `foo.bar`, true},
+		{parseAll(t, `foo.bar[0] (deposed object 00000001)`)[0], `foo.bar[0] (deposed object 00000002)`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.held.String()+" "+tt.target, func(t *testing.T) {
+			m := NewLockManager()
+			_, err := m.TryAcquire(tt.held)
+			require.NoError(t, err)
+			l, err := m.TryAcquire(parseAll(t, tt.target)...)
+			if tt.conflict {
+				require.IsType(t, &LockConflictError{}, err)
+				require.Nil(t, l)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLockModules(t *testing.T) {
+	m := NewLockManager()
+	_, err := m.TryAcquire(&Address{ModulePath: ModulePath{{Name: "a", Index: Index{UnknownKey{}}}, {Name: "b"}}})
+	require.NoError(t, err)
+
+	_, err = m.TryAcquire(&Address{ModulePath: ModulePath{{Name: "a"}}})
+	require.IsType(t, &LockConflictError{}, err)
+	_, err = m.TryAcquire(&Address{ModulePath: ModulePath{{Name: "a", Index: Index{0}}, {Name: "b", Index: Index{"x"}}, {Name: "c"}}})
+	require.IsType(t, &LockConflictError{}, err)
+	_, err = m.TryAcquire(&Address{ModulePath: ModulePath{{Name: "a", Index: Index{0}}, {Name: "c"}}})
+	require.NoError(t, err)
+}
+
+func TestLockAllOrNothing(t *testing.T) {
+	m := NewLockManager()
+	a, err := m.TryAcquire(parseAll(t, `foo.a`)...)
+	require.NoError(t, err)
+
+	_, err = m.TryAcquire(parseAll(t, `foo.b`, `foo.a[0]`)...)
+	require.Error(t, err)
+
+	// foo.b must not have been left locked by the failed attempt.
+	b, err := m.TryAcquire(parseAll(t, `foo.b`)...)
+	require.NoError(t, err)
+	require.Equal(t, "foo.b", b.Targets()[0].String())
+
+	a.Release()
+	a.Release()
+	_, err = m.TryAcquire(parseAll(t, `foo.a[0]`)...)
+	require.NoError(t, err)
+}
+
+func TestLockAcquireWaits(t *testing.T) {
+	m := NewLockManager()
+	held, err := m.TryAcquire(&Address{ModulePath: ModulePath{{Name: "a"}}})
+	require.NoError(t, err)
+
+	targets := parseAll(t, `module.a.foo.bar`, `foo.bar`)
+	acquired := make(chan *Lease)
+	go func() {
+		l, _ := m.Acquire(context.Background(), targets...)
+		acquired <- l
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired a conflicting lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	held.Release()
+	select {
+	case l := <-acquired:
+		require.Len(t, l.Targets(), 2)
+	case <-time.After(time.Second):
+		t.Fatal("lock was not acquired after release")
+	}
+}
+
+func TestLockAcquireCancel(t *testing.T) {
+	m := NewLockManager()
+	_, err := m.TryAcquire(parseAll(t, `foo.bar`)...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = m.Acquire(ctx, parseAll(t, `foo.bar[0]`)...)
+	require.Equal(t, context.DeadlineExceeded, err)
+}
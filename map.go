@@ -0,0 +1,115 @@
+package address
+
+import "sort"
+
+// Map is a map keyed by address. Unlike a map keyed by the string
+// representation, it iterates in the order Terraform lists resource
+// instances. The zero value is an empty map ready to use.
+type Map[V any] struct {
+	// entries is kept sorted by address.
+	entries []mapEntry[V]
+}
+
+type mapEntry[V any] struct {
+	addr  *Address
+	value V
+}
+
+// Len returns the number of addresses in the map.
+func (m *Map[V]) Len() int {
+	return len(m.entries)
+}
+
+// Get returns the value stored for a, if any.
+func (m *Map[V]) Get(a *Address) (V, bool) {
+	i, ok := m.search(a)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return m.entries[i].value, true
+}
+
+// Set stores v for a. The map keeps its own copy of a, so the caller may go
+// on to modify it.
+func (m *Map[V]) Set(a *Address, v V) {
+	i, ok := m.search(a)
+	if ok {
+		m.entries[i].value = v
+		return
+	}
+	m.entries = append(m.entries, mapEntry[V]{})
+	copy(m.entries[i+1:], m.entries[i:])
+	m.entries[i] = mapEntry[V]{addr: a.Clone(), value: v}
+}
+
+// Delete removes a from the map.
+func (m *Map[V]) Delete(a *Address) {
+	if i, ok := m.search(a); ok {
+		m.entries = append(m.entries[:i], m.entries[i+1:]...)
+	}
+}
+
+// Keys returns the addresses in the map, in order. The addresses are copies
+// and may be modified.
+func (m *Map[V]) Keys() []*Address {
+	keys := make([]*Address, len(m.entries))
+	for i, e := range m.entries {
+		keys[i] = e.addr.Clone()
+	}
+	return keys
+}
+
+// Range calls fn for each address and value in order, until fn returns
+// false. Neither the map nor the addresses passed to fn may be modified by
+// fn.
+func (m *Map[V]) Range(fn func(a *Address, v V) bool) {
+	for _, e := range m.entries {
+		if !fn(e.addr, e.value) {
+			return
+		}
+	}
+}
+
+// RangePrefix is like Range, but only visits addresses within the module
+// subtree at p, as selected by ModulePath.Contains.
+func (m *Map[V]) RangePrefix(p ModulePath, fn func(a *Address, v V) bool) {
+	// The subtree lies within the addresses sharing the steps of p up to
+	// the first one matching more than its own index: an unknown index, or
+	// a final step without one. Seek to the first of them.
+	n := 0
+	for n < len(p) && !p[n].Index.IsUnknown() && !(n == len(p)-1 && p[n].Index.Value == nil) {
+		n++
+	}
+	lower := &Address{ModulePath: append(ModulePath(nil), p[:n]...)}
+	if n < len(p) {
+		lower.ModulePath = append(lower.ModulePath, Module{Name: p[n].Name})
+	}
+	i, _ := m.search(lower)
+	for _, e := range m.entries[i:] {
+		mp := e.addr.ModulePath
+		if len(mp) < len(lower.ModulePath) {
+			return
+		}
+		for j := 0; j < n; j++ {
+			if mp[j] != p[j] {
+				return
+			}
+		}
+		if n < len(p) && mp[n].Name != p[n].Name {
+			return
+		}
+		if p.Contains(mp) && !fn(e.addr, e.value) {
+			return
+		}
+	}
+}
+
+// search returns the position of a in the map, or the position at which it
+// would be inserted.
+func (m *Map[V]) search(a *Address) (int, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].addr.compare(a) >= 0
+	})
+	return i, i < len(m.entries) && m.entries[i].addr.compare(a) == 0
+}
@@ -0,0 +1,117 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	var m Map[int]
+	_, ok := m.Get(parseAll(t, `foo.bar`)[0])
+	require.False(t, ok)
+
+	for i, a := range parseAll(t,
+		`module.b.foo.bar`,
+		`module.a[1].foo.bar`,
+		`foo.bar[10]`,
+		`foo.bar[2]`,
+		`module.a[0].foo.bar`,
+		`foo.bar[2]`,
+	) {
+		m.Set(a, i)
+		a.ResourceSpec.Name = "modified"
+	}
+	require.Equal(t, 5, m.Len())
+
+	v, ok := m.Get(parseAll(t, `foo.bar[2]`)[0])
+	require.True(t, ok)
+	require.Equal(t, 5, v)
+
+	var keys []string
+	for _, a := range m.Keys() {
+		keys = append(keys, a.String())
+	}
+	require.Equal(t, []string{
+		`foo.bar[2]`,
+		`foo.bar[10]`,
+		`module.a[0].foo.bar`,
+		`module.a[1].foo.bar`,
+		`module.b.foo.bar`,
+	}, keys)
+
+	var values []int
+	m.RangePrefix(ModulePath{{Name: "a"}}, func(a *Address, v int) bool {
+		values = append(values, v)
+		return true
+	})
+	require.Equal(t, []int{4, 1}, values)
+
+	values = nil
+	m.Range(func(a *Address, v int) bool {
+		values = append(values, v)
+		return len(values) < 2
+	})
+	require.Equal(t, []int{5, 2}, values)
+
+	m.Delete(parseAll(t, `module.b.foo.bar`)[0])
+	_, ok = m.Get(parseAll(t, `module.b.foo.bar`)[0])
+	require.False(t, ok)
+	require.Equal(t, 4, m.Len())
+}
+
+func TestMapRangePrefix(t *testing.T) {
+	var m Map[string]
+	all := []string{
+		`foo.bar`,
+		`module.a.foo.bar`,
+		`module.a[0].foo.bar`,
+		`module.a[0].module.b.foo.bar`,
+		`module.a[0].module.c.foo.bar`,
+		`module.a[1].foo.bar`,
+		`module.a[1].module.b.foo.bar`,
+		`module.a["x"].module.b[2].foo.bar`,
+		`module.ab.foo.bar`,
+		`module.b.foo.bar`,
+	}
+	for _, a := range parseAll(t, all...) {
+		m.Set(a, a.String())
+	}
+
+	var tests = []struct {
+		prefix   ModulePath
+		expected []string
+	}{
+		{nil, all},
+		{ModulePath{{Name: "a"}}, []string{
+			`module.a.foo.bar`,
+			`module.a[0].foo.bar`,
+			`module.a[0].module.b.foo.bar`,
+			`module.a[0].module.c.foo.bar`,
+			`module.a[1].foo.bar`,
+			`module.a[1].module.b.foo.bar`,
+			`module.a["x"].module.b[2].foo.bar`,
+		}},
+		{ModulePath{{Name: "a", Index: Index{Value: 0}}}, []string{
+			`module.a[0].foo.bar`,
+			`module.a[0].module.b.foo.bar`,
+			`module.a[0].module.c.foo.bar`,
+		}},
+		{ModulePath{{Name: "a", Index: Index{Value: UnknownKey{}}}, {Name: "b"}}, []string{
+			`module.a[0].module.b.foo.bar`,
+			`module.a[1].module.b.foo.bar`,
+			`module.a["x"].module.b[2].foo.bar`,
+		}},
+		{ModulePath{{Name: "c"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix.String(), func(t *testing.T) {
+			var visited []string
+			m.RangePrefix(tt.prefix, func(a *Address, v string) bool {
+				visited = append(visited, v)
+				return true
+			})
+			require.Equal(t, tt.expected, visited)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package address
+
+import (
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Markup is a markup language rendered by MarkupRenderer.
+type Markup int
+
+const (
+	// Markdown renders GitHub Flavored Markdown, for pull request comments.
+	Markdown Markup = iota
+	// HTML renders HTML fragments.
+	HTML
+)
+
+// LinkData is passed to the URL templates of a MarkupRenderer, once for each
+// component of an address.
+type LinkData struct {
+	// Address is the address of the component: the module at the step for
+	// a module, and the resource instance for the resource.
+	Address *Address
+	// Source and Version are the source and version of a module call, as
+	// installed by `terraform init`. They are empty for resources, and for
+	// modules that are not installed.
+	Source  string
+	Version string
+	// Pos is the block declaring the component, if found, with a
+	// slash-separated Filename relative to the configuration root.
+	Pos SourcePos
+}
+
+// MarkupRenderer renders addresses as Markdown or HTML, with each component
+// linking to a URL built from a template. Templates are executed with
+// LinkData, and may use FuncMap for addresses; a component whose template is
+// nil or produces an empty URL is not linked. For example, to link resources
+// to their declaration in a GitHub repository:
+//
+//	{{with .Pos.Filename}}https://github.com/acme/infra/blob/main/{{.}}#L{{$.Pos.Line}}{{end}}
+type MarkupRenderer struct {
+	Markup Markup
+	// ModuleURL and ResourceURL build the links of module steps and of the
+	// resource.
+	ModuleURL   *template.Template
+	ResourceURL *template.Template
+	// Root is the directory of the root module, in which declarations and
+	// installed modules are looked up. Without it, Source, Version and Pos
+	// are left empty.
+	Root string
+	// Collapse is the number of module steps beyond which the module path is
+	// collapsed, showing only the last step until expanded. Zero never
+	// collapses.
+	Collapse int
+}
+
+// Render renders a single address.
+func (r *MarkupRenderer) Render(a *Address) (string, error) {
+	return r.render(a, r.manifest())
+}
+
+// RenderList renders addrs as a list.
+func (r *MarkupRenderer) RenderList(addrs []*Address) (string, error) {
+	manifest := r.manifest()
+	var b strings.Builder
+	if r.Markup == HTML {
+		b.WriteString("<ul>\n")
+	}
+	for _, a := range addrs {
+		s, err := r.render(a, manifest)
+		if err != nil {
+			return "", err
+		}
+		if r.Markup == HTML {
+			b.WriteString("<li>" + s + "</li>\n")
+		} else {
+			// Indent continuation lines into the list item.
+			for i, line := range strings.Split(s, "\n") {
+				switch {
+				case i == 0:
+					b.WriteString("- ")
+				case line != "":
+					b.WriteString("  ")
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	if r.Markup == HTML {
+		b.WriteString("</ul>\n")
+	}
+	return b.String(), nil
+}
+
+func (r *MarkupRenderer) manifest() *ModuleManifest {
+	if r.Root == "" {
+		return nil
+	}
+	m, _ := LoadModuleManifest(r.Root)
+	return m
+}
+
+func (r *MarkupRenderer) render(a *Address, manifest *ModuleManifest) (string, error) {
+	var decl *Declaration
+	if r.Root != "" && !a.IsModule() {
+		decl, _ = Locate(r.Root, a)
+	}
+
+	var steps []string
+	for i := range a.ModulePath {
+		data := LinkData{Address: &Address{ModulePath: a.Clone().ModulePath[:i+1]}}
+		if manifest != nil {
+			if rec, err := manifest.Module(a.ModulePath[:i+1]); err == nil {
+				data.Source, data.Version = rec.Source, rec.Version
+			}
+		}
+		if decl != nil {
+			data.Pos = r.relative(decl.Modules[i])
+		}
+		s, err := r.link(r.ModuleURL, data, a.ModulePath[i].String())
+		if err != nil {
+			return "", err
+		}
+		steps = append(steps, s)
+	}
+
+	var resource string
+	if !a.IsModule() {
+		inst := a.Clone()
+		inst.DeposedKey = ""
+		data := LinkData{Address: inst}
+		if decl != nil {
+			data.Pos = r.relative(decl.Resource)
+		}
+		var err error
+		if resource, err = r.link(r.ResourceURL, data, a.ResourceSpec.String()); err != nil {
+			return "", err
+		}
+		if a.DeposedKey != "" {
+			resource += r.escape(" (deposed object " + a.DeposedKey + ")")
+		}
+	}
+
+	parts := append([]string(nil), steps...)
+	if !a.IsModule() {
+		parts = append(parts, resource)
+	}
+	full := strings.Join(parts, ".")
+	if r.Collapse == 0 || len(steps) <= r.Collapse {
+		return r.code(full), nil
+	}
+	summary := r.escape("….") + strings.Join(parts[len(steps)-1:], ".")
+	if r.Markup == HTML {
+		return "<details><summary>" + r.code(summary) + "</summary>" + r.code(full) + "</details>", nil
+	}
+	// GitHub renders Markdown within details elements when it is separated
+	// from the tags by blank lines.
+	return "<details><summary>" + r.code(summary) + "</summary>\n\n" + r.code(full) + "\n\n</details>", nil
+}
+
+// link renders text linking to the URL built from t.
+func (r *MarkupRenderer) link(t *template.Template, data LinkData, text string) (string, error) {
+	var url string
+	if t != nil {
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", err
+		}
+		url = strings.TrimSpace(b.String())
+	}
+	if url == "" {
+		return r.escape(text), nil
+	}
+	if r.Markup == HTML {
+		return `<a href="` + html.EscapeString(url) + `">` + r.escape(text) + "</a>", nil
+	}
+	return "[" + r.escape(text) + "](" + markdownURLEscaper.Replace(url) + ")", nil
+}
+
+// code marks s, rendered address components, as code. Markdown code spans
+// cannot hold links, so Markdown addresses are rendered as plain text.
+func (r *MarkupRenderer) code(s string) string {
+	if r.Markup == HTML {
+		return "<code>" + s + "</code>"
+	}
+	return s
+}
+
+func (r *MarkupRenderer) escape(s string) string {
+	if r.Markup == HTML {
+		return html.EscapeString(s)
+	}
+	return markdownEscaper.Replace(s)
+}
+
+// relative makes the filename of p relative to the root module.
+func (r *MarkupRenderer) relative(p SourcePos) SourcePos {
+	if rel, err := filepath.Rel(r.Root, p.Filename); err == nil && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		p.Filename = filepath.ToSlash(rel)
+	}
+	return p
+}
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+		`<`, `&lt;`, `>`, `&gt;`, `&`, `&amp;`, `"`, `&quot;`,
+	)
+	markdownURLEscaper = strings.NewReplacer(` `, `%20`, `(`, `%28`, `)`, `%29`)
+)
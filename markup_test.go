@@ -0,0 +1,109 @@
+package address
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkupRenderer(t *testing.T) {
+	root := t.TempDir()
+	for name, src := range sourceFiles {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+	}
+
+	moduleURL := template.Must(template.New("module").Parse(
+		`{{if .Version}}https://{{.Source}}/{{.Version}}{{else}}{{with .Pos.Filename}}https://vcs.example/blob/main/{{.}}#L{{$.Pos.Line}}{{end}}{{end}}`))
+	resourceURL := template.Must(template.New("resource").Funcs(FuncMap()).Parse(
+		`{{with .Pos.Filename}}https://vcs.example/blob/main/{{.}}#L{{$.Pos.Line}}{{else}}https://docs.example/{{addrType $.Address}}{{end}}`))
+
+	var tests = []struct {
+		name     string
+		renderer MarkupRenderer
+		addr     string
+		expected string
+	}{
+		{
+			"markdown",
+			MarkupRenderer{Markup: Markdown, ModuleURL: moduleURL, ResourceURL: resourceURL, Root: root},
+			`module.net.module.inner.aws_route.r`,
+			`[module.net](https://vcs.example/blob/main/main.tf#L2).[module.inner](https://vcs.example/blob/main/modules/net/main.tf#L1).[aws\_route.r](https://vcs.example/blob/main/modules/inner/main.tf#L1)`,
+		},
+		{
+			"markdown registry module",
+			MarkupRenderer{Markup: Markdown, ModuleURL: moduleURL, ResourceURL: resourceURL, Root: root},
+			`module.remote.aws_instance.server[0] (deposed object 00000001)`,
+			`[module.remote](https://registry.terraform.io/hashicorp/consul/aws/0.1.0).[aws\_instance.server\[0\]](https://vcs.example/blob/main/.terraform/modules/remote/main.tf#L3) (deposed object 00000001)`,
+		},
+		{
+			"markdown undeclared",
+			MarkupRenderer{Markup: Markdown, ModuleURL: moduleURL, ResourceURL: resourceURL, Root: root},
+			`module.gone["x"].aws_instance.web`,
+			`module.gone\[&quot;x&quot;\].[aws\_instance.web](https://docs.example/aws_instance)`,
+		},
+		{
+			"html",
+			MarkupRenderer{Markup: HTML, ResourceURL: resourceURL},
+			`module.a["<b>"].data.aws_ami.x`,
+			`<code>module.a[&#34;&lt;b&gt;&#34;].<a href="https://docs.example/aws_ami">data.aws_ami.x</a></code>`,
+		},
+		{
+			"html collapsed",
+			MarkupRenderer{Markup: HTML, Collapse: 1},
+			`module.a.module.b.foo.bar`,
+			`<details><summary><code>….module.b.foo.bar</code></summary><code>module.a.module.b.foo.bar</code></details>`,
+		},
+		{
+			"markdown collapsed",
+			MarkupRenderer{Markup: Markdown, Collapse: 1},
+			`module.a.module.b.foo.bar`,
+			"<details><summary>….module.b.foo.bar</summary>\n\nmodule.a.module.b.foo.bar\n\n</details>",
+		},
+		{
+			"not collapsed",
+			MarkupRenderer{Markup: Markdown, Collapse: 2},
+			`module.a.module.b.foo.bar`,
+			`module.a.module.b.foo.bar`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAddress(tt.addr)
+			require.NoError(t, err)
+			s, err := tt.renderer.Render(a)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, s)
+		})
+	}
+}
+
+func TestMarkupRendererList(t *testing.T) {
+	addrs := parseAll(t, `foo.bar`, `module.a.module.b.foo.bar`)
+	addrs = append(addrs, &Address{ModulePath: ModulePath{{Name: "a"}, {Name: "c"}}})
+
+	r := &MarkupRenderer{Markup: Markdown, Collapse: 1}
+	s, err := r.RenderList(addrs)
+	require.NoError(t, err)
+	require.Equal(t, `- foo.bar
+- <details><summary>….module.b.foo.bar</summary>
+
+  module.a.module.b.foo.bar
+
+  </details>
+- <details><summary>….module.c</summary>
+
+  module.a.module.c
+
+  </details>
+`, s)
+
+	r = &MarkupRenderer{Markup: HTML}
+	s, err = r.RenderList(addrs[:1])
+	require.NoError(t, err)
+	require.Equal(t, "<ul>\n<li><code>foo.bar</code></li>\n</ul>\n", s)
+}
@@ -0,0 +1,80 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleRecord describes an installed module call, as recorded by
+// `terraform init` in `.terraform/modules/modules.json`.
+type ModuleRecord struct {
+	// Key is the module call path, e.g. `network.subnets`. The root module
+	// has an empty key.
+	Key string
+	// Source and Version are as given in the module block. Version is empty
+	// for modules that are not from a registry.
+	Source  string
+	Version string
+	// Dir is the directory containing the module's configuration.
+	Dir string
+}
+
+// ModuleManifest maps module call paths to the modules installed for a
+// root module.
+type ModuleManifest struct {
+	records map[string]ModuleRecord
+}
+
+// LoadModuleManifest reads the module manifest of the root module in the
+// directory root. The directories of the returned records are relative to the
+// working directory, like root itself.
+func LoadModuleManifest(root string) (*ModuleManifest, error) {
+	b, err := os.ReadFile(filepath.Join(root, ".terraform", "modules", "modules.json"))
+	if err != nil {
+		return nil, err
+	}
+	var v struct {
+		Modules []ModuleRecord
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("invalid module manifest: %w", err)
+	}
+	m := &ModuleManifest{records: make(map[string]ModuleRecord, len(v.Modules))}
+	for _, r := range v.Modules {
+		if !filepath.IsAbs(r.Dir) {
+			r.Dir = filepath.Join(root, filepath.FromSlash(r.Dir))
+		}
+		m.records[r.Key] = r
+	}
+	return m, nil
+}
+
+// Module returns the record of the module at p. Instance keys are ignored,
+// since every instance of a module call shares its configuration.
+func (m *ModuleManifest) Module(p ModulePath) (ModuleRecord, error) {
+	key := p.CallKey()
+	r, ok := m.records[key]
+	if !ok {
+		return ModuleRecord{}, fmt.Errorf("module %q is not installed", key)
+	}
+	return r, nil
+}
+
+// Resolve returns the record of the module that declares the resource at a.
+func (m *ModuleManifest) Resolve(a *Address) (ModuleRecord, error) {
+	return m.Module(a.ModulePath)
+}
+
+// CallKey returns the module call path of m, the names of each module joined
+// by `.` without instance keys. This is the key Terraform uses for the
+// module in its module manifest.
+func (m ModulePath) CallKey() string {
+	names := make([]string, len(m))
+	for i, c := range m {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ".")
+}
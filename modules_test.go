@@ -0,0 +1,58 @@
+package address
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const modulesJSON = `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"network","Source":"registry.terraform.io/hashicorp/consul/aws","Version":"0.1.0","Dir":".terraform/modules/network"},
+	{"Key":"network.subnets","Source":"./subnets","Dir":".terraform/modules/network/subnets"}
+]}`
+
+func TestModuleManifest(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".terraform", "modules"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".terraform", "modules", "modules.json"), []byte(modulesJSON), 0o644))
+
+	m, err := LoadModuleManifest(root)
+	require.NoError(t, err)
+
+	var tests = []struct {
+		addr    string
+		dir     string
+		source  string
+		version string
+	}{
+		{`foo.bar`, root, "", ""},
+		{`module.network["a"].foo.bar[0]`, filepath.Join(root, ".terraform", "modules", "network"), "registry.terraform.io/hashicorp/consul/aws", "0.1.0"},
+		{`module.network[0].module.subnets[*].foo.bar`, filepath.Join(root, ".terraform", "modules", "network", "subnets"), "./subnets", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			a, err := NewAddress(tt.addr)
+			require.NoError(t, err)
+			r, err := m.Resolve(a)
+			require.NoError(t, err)
+			require.Equal(t, tt.dir, r.Dir)
+			require.Equal(t, tt.source, r.Source)
+			require.Equal(t, tt.version, r.Version)
+		})
+	}
+
+	a, err := NewAddress(`module.missing.foo.bar`)
+	require.NoError(t, err)
+	_, err = m.Resolve(a)
+	require.Error(t, err)
+}
+
+func TestCallKey(t *testing.T) {
+	a, err := NewAddress(`module.a[0].module.b["x"].foo.bar`)
+	require.NoError(t, err)
+	require.Equal(t, "a.b", a.ModulePath.CallKey())
+	require.Equal(t, "", ModulePath{}.CallKey())
+}
@@ -0,0 +1,104 @@
+package address
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OwnerRule assigns owners to the addresses matching a pattern.
+type OwnerRule struct {
+	Pattern *Pattern
+	// Owners may be empty, in which case matching addresses have no owner.
+	Owners []string
+	// Line is the line of the rule in its file.
+	Line int
+}
+
+// Owners maps addresses to their owners, like a CODEOWNERS file maps paths.
+// Each line of an owners file holds a pattern followed by its owners:
+//
+//	# Comments start with a hash.
+//	**                      @platform
+//	module.payments.**      @payments-team
+//	**.aws_iam_role.*       @security @payments-team
+//
+// As in CODEOWNERS, the last rule matching an address wins.
+type Owners struct {
+	Rules []OwnerRule
+}
+
+// ParseOwners reads an owners file from r.
+func ParseOwners(r io.Reader) (*Owners, error) {
+	o := &Owners{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(stripComment(s.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		p, err := ParsePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		o.Rules = append(o.Rules, OwnerRule{Pattern: p, Owners: fields[1:], Line: line})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// stripComment removes a trailing comment from line. A hash within a quoted
+// instance key does not start a comment.
+func stripComment(line string) string {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == '#' && !quoted:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Rule returns the rule that applies to a, the last one matching it.
+func (o *Owners) Rule(a *Address) (OwnerRule, bool) {
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if o.Rules[i].Pattern.Match(a) {
+			return o.Rules[i], true
+		}
+	}
+	return OwnerRule{}, false
+}
+
+// Owners returns the owners of a. Returns nil if a has no owner.
+func (o *Owners) Owners(a *Address) []string {
+	r, _ := o.Rule(a)
+	return r.Owners
+}
+
+// Unmatched returns the rules whose pattern matches none of addrs, such as
+// the addresses in a state. These rules are likely to be stale or
+// mistyped.
+func (o *Owners) Unmatched(addrs []*Address) []OwnerRule {
+	var unmatched []OwnerRule
+	for _, r := range o.Rules {
+		matched := false
+		for _, a := range addrs {
+			if r.Pattern.Match(a) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, r)
+		}
+	}
+	return unmatched
+}
@@ -0,0 +1,60 @@
+package address
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ownersFile = `# Default owners.
+**                      @platform
+
+module.payments.**      @payments-team
+**.aws_iam_role.*       @security @payments-team # IAM needs review
+module.payments.aws_s3_bucket.scratch
+module.legacy.**        @nobody
+`
+
+func TestOwners(t *testing.T) {
+	o, err := ParseOwners(strings.NewReader(ownersFile))
+	require.NoError(t, err)
+	require.Len(t, o.Rules, 5)
+
+	var tests = []struct {
+		addr   string
+		owners []string
+	}{
+		{`aws_instance.a`, []string{"@platform"}},
+		{`module.payments[0].aws_instance.a`, []string{"@payments-team"}},
+		{`module.payments.aws_iam_role.a`, []string{"@security", "@payments-team"}},
+		{`module.payments.aws_s3_bucket.scratch`, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			require.Equal(t, tt.owners, o.Owners(parseAll(t, tt.addr)[0]))
+		})
+	}
+
+	r, ok := o.Rule(parseAll(t, `module.payments.aws_iam_role.a`)[0])
+	require.True(t, ok)
+	require.Equal(t, 5, r.Line)
+
+	unmatched := o.Unmatched(parseAll(t, `aws_instance.a`, `module.payments.aws_iam_role.a`))
+	require.Len(t, unmatched, 2)
+	require.Equal(t, "module.payments.aws_s3_bucket.scratch", unmatched[0].Pattern.String())
+	require.Equal(t, 7, unmatched[1].Line)
+}
+
+func TestParseOwnersComments(t *testing.T) {
+	o, err := ParseOwners(strings.NewReader(`foo.bar["a#b"] @x # c` + "\n" + `foo.baz["a\"#"] @y #c` + "\n"))
+	require.NoError(t, err)
+	require.Len(t, o.Rules, 2)
+	require.Equal(t, []string{"@x"}, o.Owners(parseAll(t, `foo.bar["a#b"]`)[0]))
+	require.Equal(t, []string{"@y"}, o.Owners(parseAll(t, `foo.baz["a\"#"]`)[0]))
+}
+
+func TestParseOwnersError(t *testing.T) {
+	_, err := ParseOwners(strings.NewReader("foo.bar @a\nfoo @b\n"))
+	require.EqualError(t, err, `line 2: invalid pattern "foo": expected resource type and name`)
+}
@@ -0,0 +1,213 @@
+package address
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Pattern matches addresses. Patterns are written like addresses, with these
+// additions:
+//
+//   - Module names, resource types and resource names may contain the
+//     wildcards `*` and `?`, e.g. `module.app-*.aws_s3_bucket.*`.
+//   - `[*]` matches any instance key. A component without an index matches
+//     every instance, whatever its key.
+//   - `**` matches any number of module steps and the resource, so that
+//     `module.payments.**` matches everything within module.payments and
+//     `**.aws_iam_role.*` matches IAM roles in any module.
+type Pattern struct {
+	source   string
+	elements []patternElement
+}
+
+type patternElementKind int
+
+const (
+	patternModule patternElementKind = iota
+	patternResource
+	patternAny
+)
+
+type patternElement struct {
+	kind patternElementKind
+	mode ResourceMode
+	// typ is the resource type glob; name is the module or resource name
+	// glob.
+	typ  string
+	name string
+	// index is nil to match every instance.
+	index *Index
+}
+
+// ParsePattern parses a pattern. Returns an error if the pattern is
+// malformed.
+func ParsePattern(s string) (*Pattern, error) {
+	parts, err := splitPattern(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pattern{source: s}
+	for len(parts) > 0 {
+		var e patternElement
+		switch {
+		case parts[0] == "**":
+			e.kind = patternAny
+			parts = parts[1:]
+		case parts[0] == "module" && len(parts) > 1:
+			e.kind = patternModule
+			if e.name, e.index, err = splitPatternIndex(parts[1]); err != nil {
+				return nil, err
+			}
+			parts = parts[2:]
+		default:
+			e.kind = patternResource
+			if parts[0] == "data" && len(parts) == 3 {
+				e.mode = DataResourceMode
+				parts = parts[1:]
+			}
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid pattern %q: expected resource type and name", s)
+			}
+			e.typ = parts[0]
+			if e.name, e.index, err = splitPatternIndex(parts[1]); err != nil {
+				return nil, err
+			}
+			parts = nil
+		}
+		for _, glob := range []string{e.typ, e.name} {
+			if _, err := path.Match(glob, ""); err != nil || strings.ContainsAny(glob, "/") {
+				return nil, fmt.Errorf("invalid pattern %q: malformed name %q", s, glob)
+			}
+		}
+		if e.kind != patternAny && (e.name == "" || (e.kind == patternResource && e.typ == "")) {
+			return nil, fmt.Errorf("invalid pattern %q: empty name", s)
+		}
+		p.elements = append(p.elements, e)
+	}
+	if len(p.elements) == 0 {
+		return nil, fmt.Errorf("invalid pattern %q: empty pattern", s)
+	}
+	if last := p.elements[len(p.elements)-1].kind; last == patternModule {
+		return nil, fmt.Errorf("invalid pattern %q: must end with a resource or **", s)
+	}
+	return p, nil
+}
+
+// String returns the pattern as it was parsed.
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Match reports whether p matches the address a. A module address, with an
+// empty ResourceSpec, is only matched through a trailing `**`.
+func (p *Pattern) Match(a *Address) bool {
+	r := &a.ResourceSpec
+	if a.IsModule() {
+		r = nil
+	}
+	return matchPattern(p.elements, a.ModulePath, r)
+}
+
+// matchPattern matches elements against the remaining module steps mp and
+// resource r, which is nil once matched.
+func matchPattern(elements []patternElement, mp ModulePath, r *ResourceSpec) bool {
+	if len(elements) == 0 {
+		return len(mp) == 0 && r == nil
+	}
+	e := elements[0]
+	switch e.kind {
+	case patternAny:
+		// Try consuming each possible number of module steps, and then the
+		// resource too.
+		for i := 0; i <= len(mp); i++ {
+			if matchPattern(elements[1:], mp[i:], r) {
+				return true
+			}
+		}
+		return matchPattern(elements[1:], nil, nil)
+	case patternModule:
+		if len(mp) == 0 || !globMatch(e.name, mp[0].Name) || !e.matchIndex(mp[0].Index) {
+			return false
+		}
+		return matchPattern(elements[1:], mp[1:], r)
+	default:
+		if len(mp) != 0 || r == nil {
+			return false
+		}
+		if r.Mode != e.mode || !globMatch(e.typ, r.Type) || !globMatch(e.name, r.Name) || !e.matchIndex(r.Index) {
+			return false
+		}
+		return matchPattern(elements[1:], nil, nil)
+	}
+}
+
+func (e patternElement) matchIndex(i Index) bool {
+	switch {
+	case e.index == nil:
+		return true
+	case e.index.IsUnknown():
+		return i.Value != nil
+	default:
+		return *e.index == i
+	}
+}
+
+func globMatch(glob, name string) bool {
+	ok, _ := path.Match(glob, name)
+	return ok
+}
+
+// splitPattern splits a pattern at the dots that are not within an index.
+func splitPattern(s string) ([]string, error) {
+	var (
+		parts   []string
+		start   int
+		bracket bool
+		quoted  bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"' && bracket:
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			bracket = true
+		case c == ']':
+			bracket = false
+		case c == '.' && !bracket:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if bracket || quoted {
+		return nil, fmt.Errorf("invalid pattern %q: unterminated index", s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// splitPatternIndex splits a name from its optional index.
+func splitPatternIndex(s string) (string, *Index, error) {
+	open := strings.IndexByte(s, '[')
+	if open < 0 {
+		return s, nil, nil
+	}
+	if !strings.HasSuffix(s, "]") {
+		return "", nil, fmt.Errorf("invalid index in %q", s)
+	}
+	name, key := s[:open], s[open+1:len(s)-1]
+	if key == "*" {
+		return name, &Index{Value: UnknownKey{}}, nil
+	}
+	if n, err := strconv.Atoi(key); err == nil {
+		return name, &Index{Value: n}, nil
+	}
+	str, err := strconv.Unquote(key)
+	if err != nil || !strings.HasPrefix(key, `"`) {
+		return "", nil, fmt.Errorf("invalid index in %q", s)
+	}
+	return name, &Index{Value: str}, nil
+}
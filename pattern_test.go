@@ -0,0 +1,78 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternMatch(t *testing.T) {
+	var tests = []struct {
+		pattern  string
+		addr     string
+		expected bool
+	}{
+		{`foo.bar`, `foo.bar`, true},
+		{`foo.bar`, `foo.bar[0]`, true},
+		{`foo.bar`, `module.a.foo.bar`, false},
+		{`foo.bar[0]`, `foo.bar[1]`, false},
+		{`foo.bar["a.b"]`, `foo.bar["a.b"]`, true},
+		{`foo.bar[*]`, `foo.bar["x"]`, true},
+		{`foo.bar[*]`, `foo.bar`, false},
+		{`foo.*`, `foo.bar`, true},
+		{`f?o.b*`, `foo.baz`, true},
+		{`*.*`, `data.foo.bar`, false},
+		{`data.*.*`, `data.foo.bar`, true},
+		{`module.a.foo.bar`, `module.a[2].foo.bar`, true},
+		{`module.a[1].foo.bar`, `module.a[2].foo.bar`, false},
+		{`module.app-*.foo.bar`, `module.app-x.foo.bar`, true},
+		{`module.payments.**`, `module.payments["x"].module.b.foo.bar[0]`, true},
+		{`module.payments.**`, `module.payments.foo.bar`, true},
+		{`module.payments.**`, `module.other.foo.bar`, false},
+		{`module.payments.**`, `foo.bar`, false},
+		{`**.aws_iam_role.*`, `aws_iam_role.a`, true},
+		{`**.aws_iam_role.*`, `module.a.module.b.aws_iam_role.a`, true},
+		{`**.aws_iam_role.*`, `module.a.aws_s3_bucket.a`, false},
+		{`module.a.**.foo.bar`, `module.a.module.b.foo.bar`, true},
+		{`**`, `module.a.foo.bar`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.addr, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.pattern, p.String())
+			require.Equal(t, tt.expected, p.Match(parseAll(t, tt.addr)[0]))
+		})
+	}
+}
+
+func TestPatternMatchModule(t *testing.T) {
+	p, err := ParsePattern(`module.a.**`)
+	require.NoError(t, err)
+	require.True(t, p.Match(&Address{ModulePath: ModulePath{{Name: "a"}}}))
+	require.True(t, p.Match(&Address{ModulePath: ModulePath{{Name: "a"}, {Name: "b"}}}))
+
+	p, err = ParsePattern(`**.foo.bar`)
+	require.NoError(t, err)
+	require.False(t, p.Match(&Address{ModulePath: ModulePath{{Name: "a"}}}))
+}
+
+func TestInvalidPatterns(t *testing.T) {
+	var tests = []string{
+		``,
+		`foo`,
+		`module.a`,
+		`foo.bar.baz`,
+		`foo.bar[`,
+		`foo.bar["a]`,
+		`foo.bar[a]`,
+		`module.[0].foo.bar`,
+		`foo.[.bar`,
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			_, err := ParsePattern(tt)
+			require.Error(t, err)
+		})
+	}
+}
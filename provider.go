@@ -0,0 +1,161 @@
+package address
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultProviderRegistryHost is the hostname of providers whose source
+// address omits it.
+const DefaultProviderRegistryHost = "registry.terraform.io"
+
+// Provider is the fully-qualified name, or source address, of a provider,
+// as in `registry.terraform.io/hashicorp/aws`.
+//
+// Providers recorded by Terraform versions before 0.13 are only known by
+// their type. Such legacy providers have an empty Hostname and Namespace,
+// or the namespace `-` once Terraform 0.13 has upgraded the state.
+type Provider struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+// ParseProvider parses a provider source address. As in a
+// `required_providers` block, the hostname defaults to
+// DefaultProviderRegistryHost and a bare type implies the `hashicorp`
+// namespace.
+func ParseProvider(s string) (Provider, error) {
+	parts := strings.Split(s, "/")
+	var p Provider
+	switch len(parts) {
+	case 1:
+		p = ImpliedProvider(parts[0])
+	case 2:
+		p = Provider{Hostname: DefaultProviderRegistryHost, Namespace: parts[0], Type: parts[1]}
+	case 3:
+		p = Provider{Hostname: parts[0], Namespace: parts[1], Type: parts[2]}
+	default:
+		return Provider{}, fmt.Errorf("invalid provider source %q", s)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Provider{}, fmt.Errorf("invalid provider source %q", s)
+		}
+	}
+	if !isIdentifier(p.Type) {
+		return Provider{}, fmt.Errorf("invalid provider source %q: invalid type %q", s, p.Type)
+	}
+	return p, nil
+}
+
+// ImpliedProvider returns the provider Terraform implies for the local name
+// typ when no `required_providers` entry declares it.
+func ImpliedProvider(typ string) Provider {
+	return Provider{Hostname: DefaultProviderRegistryHost, Namespace: "hashicorp", Type: typ}
+}
+
+// IsLegacy reports whether p is only known by its type.
+func (p Provider) IsLegacy() bool {
+	return p.Hostname == "" || p.Namespace == "-"
+}
+
+// String representation of the provider. Legacy providers without a hostname
+// are represented by their type alone.
+func (p Provider) String() string {
+	if p.Hostname == "" {
+		return p.Type
+	}
+	return p.Hostname + "/" + p.Namespace + "/" + p.Type
+}
+
+// ProviderConfig is the address of a provider configuration, as recorded for
+// each resource in state. Its two forms are
+//
+//	module.a.provider.aws.west                                  (before 0.13)
+//	module.a.provider["registry.terraform.io/hashicorp/aws"].west
+//
+// where the module path and the alias are optional. The module path names
+// module calls, so its steps have no instance keys.
+type ProviderConfig struct {
+	ModulePath ModulePath
+	Provider   Provider
+	// Alias is empty for the default configuration of the provider.
+	Alias string
+}
+
+// ParseProviderConfig parses a provider configuration address of either
+// form. Returns an error if the address is malformed, or if its module path
+// has instance keys.
+func ParseProviderConfig(s string) (*ProviderConfig, error) {
+	c, err := Parse(s, []byte(s), Entrypoint("ProviderConfig"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider address %q: %w", s, err)
+	}
+	// Provider configurations belong to module calls, not to their
+	// instances.
+	for _, m := range c.(*ProviderConfig).ModulePath {
+		if m.Index.Value != nil {
+			return nil, fmt.Errorf("invalid provider address %q: module %s cannot have an instance key", s, m.Name)
+		}
+	}
+	return c.(*ProviderConfig), nil
+}
+
+// Normalize returns a copy of c with a legacy provider replaced by its
+// fully-qualified name. The name is looked up by type in providers, as
+// declared by `required_providers`, and is otherwise the implied one.
+// Configurations that are already fully-qualified are copied as they are.
+func (c *ProviderConfig) Normalize(providers map[string]Provider) *ProviderConfig {
+	n := c.Clone()
+	if !n.Provider.IsLegacy() {
+		return n
+	}
+	if p, ok := providers[n.Provider.Type]; ok {
+		n.Provider = p
+	} else {
+		n.Provider = ImpliedProvider(n.Provider.Type)
+	}
+	return n
+}
+
+// Clone copies the memory containing the provider configuration.
+func (c *ProviderConfig) Clone() *ProviderConfig {
+	mp := make(ModulePath, len(c.ModulePath))
+	copy(mp, c.ModulePath)
+	return &ProviderConfig{ModulePath: mp, Provider: c.Provider, Alias: c.Alias}
+}
+
+// String representation of the provider configuration, in the legacy form if
+// the provider has no hostname.
+func (c *ProviderConfig) String() string {
+	dst := c.ModulePath.appendText(nil)
+	if len(c.ModulePath) > 0 {
+		dst = append(dst, '.')
+	}
+	dst = append(dst, "provider"...)
+	if c.Provider.Hostname == "" {
+		dst = append(dst, '.')
+		dst = append(dst, c.Provider.Type...)
+	} else {
+		dst = append(dst, '[')
+		dst = strconv.AppendQuote(dst, c.Provider.String())
+		dst = append(dst, ']')
+	}
+	if c.Alias != "" {
+		dst = append(dst, '.')
+		dst = append(dst, c.Alias...)
+	}
+	return string(dst)
+}
+
+// isIdentifier reports whether s is a valid Terraform identifier.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !isIdentRune(r, i == 0) {
+			return false
+		}
+	}
+	return s != ""
+}
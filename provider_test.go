@@ -0,0 +1,117 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProviderConfig(t *testing.T) {
+	var tests = []struct {
+		addr     string
+		expected *ProviderConfig
+	}{
+		{
+			`provider.aws`,
+			&ProviderConfig{Provider: Provider{Type: "aws"}},
+		},
+		{
+			`provider.aws.west`,
+			&ProviderConfig{Provider: Provider{Type: "aws"}, Alias: "west"},
+		},
+		{
+			`module.a.provider.aws`,
+			&ProviderConfig{ModulePath: ModulePath{{Name: "a"}}, Provider: Provider{Type: "aws"}},
+		},
+		{
+			`provider["registry.terraform.io/hashicorp/aws"].west`,
+			&ProviderConfig{Provider: ImpliedProvider("aws"), Alias: "west"},
+		},
+		{
+			`module.a.module.b.provider["example.com/acme/foo"]`,
+			&ProviderConfig{
+				ModulePath: ModulePath{{Name: "a"}, {Name: "b"}},
+				Provider:   Provider{Hostname: "example.com", Namespace: "acme", Type: "foo"},
+			},
+		},
+		{
+			`module.-a.provider.aws`,
+			&ProviderConfig{ModulePath: ModulePath{{Name: "-a"}}, Provider: Provider{Type: "aws"}},
+		},
+		{
+			`provider["registry.terraform.io/-/aws"]`,
+			&ProviderConfig{Provider: Provider{Hostname: "registry.terraform.io", Namespace: "-", Type: "aws"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			c, err := ParseProviderConfig(tt.addr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, c)
+			require.Equal(t, tt.addr, c.String())
+		})
+	}
+}
+
+func TestParseProviderConfigInvalid(t *testing.T) {
+	for _, addr := range []string{
+		``,
+		`aws_instance.web`,
+		`provider`,
+		`provider.`,
+		`provider.aws.`,
+		`provider.aws.west.east`,
+		`provider["aws"]`,
+		`provider["registry.terraform.io/hashicorp/aws"`,
+		`provider["registry.terraform.io/hashicorp/aws"]west`,
+		`module.provider.aws`,
+		`module.a[x].provider.aws`,
+		`module.a["x].provider.aws`,
+		`module.a[*].provider.aws`,
+		`module.a[0].module.b["x.y"].provider["example.com/acme/foo"]`,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			_, err := ParseProviderConfig(addr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestProviderConfigNormalize(t *testing.T) {
+	providers := map[string]Provider{
+		"google": {Hostname: "registry.terraform.io", Namespace: "acme", Type: "google"},
+	}
+	var tests = []struct {
+		addr     string
+		expected string
+	}{
+		{`provider.aws`, `provider["registry.terraform.io/hashicorp/aws"]`},
+		{`module.a.provider.aws.west`, `module.a.provider["registry.terraform.io/hashicorp/aws"].west`},
+		{`provider.google`, `provider["registry.terraform.io/acme/google"]`},
+		{`provider["registry.terraform.io/-/google"].x`, `provider["registry.terraform.io/acme/google"].x`},
+		{`provider["example.com/acme/aws"]`, `provider["example.com/acme/aws"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			c, err := ParseProviderConfig(tt.addr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, c.Normalize(providers).String())
+			require.Equal(t, tt.addr, c.String())
+		})
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	p, err := ParseProvider("aws")
+	require.NoError(t, err)
+	require.Equal(t, ImpliedProvider("aws"), p)
+
+	p, err = ParseProvider("acme/foo")
+	require.NoError(t, err)
+	require.Equal(t, "registry.terraform.io/acme/foo", p.String())
+
+	for _, s := range []string{"", "a//b", "a/b/c/d", "a/b/-c"} {
+		_, err = ParseProvider(s)
+		require.Error(t, err, s)
+	}
+}
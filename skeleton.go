@@ -0,0 +1,269 @@
+package address
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GenerateConfig returns skeleton configuration that declares every resource
+// in addrs, along with the module calls leading to them. Files are keyed by
+// their slash-separated path relative to the root module: the root module is
+// written to `main.tf` and the module called `a` to `modules/a/main.tf`.
+//
+// The repetition of each resource and module call is inferred from the
+// instance keys of addrs: integer keys give `count` and string keys give
+// `for_each` over the set of keys. An error is returned if the keys of a
+// resource or module call are unknown or of mixed kinds.
+func GenerateConfig(addrs []*Address) (map[string][]byte, error) {
+	modules := make(map[string]*skeletonModule)
+	var module func(p ModulePath) *skeletonModule
+	module = func(p ModulePath) *skeletonModule {
+		key := p.CallKey()
+		if m, ok := modules[key]; ok {
+			return m
+		}
+		m := &skeletonModule{
+			dir:       skeletonDir(p),
+			calls:     make(map[string]*skeletonBlock),
+			resources: make(map[string]*skeletonBlock),
+		}
+		modules[key] = m
+		if len(p) > 0 {
+			parent := module(p[:len(p)-1])
+			parent.calls[p[len(p)-1].Name] = &skeletonBlock{}
+		}
+		return m
+	}
+
+	for _, a := range addrs {
+		for i, step := range a.ModulePath {
+			module(a.ModulePath[:i+1])
+			call := module(a.ModulePath[:i]).calls[step.Name]
+			call.keys = append(call.keys, step.Index)
+		}
+		m := module(a.ModulePath)
+		if a.IsModule() {
+			// The module and the calls leading to it are declared, but
+			// there is no resource to declare.
+			continue
+		}
+		r := a.ResourceSpec
+		key := r.Mode.String() + "." + r.Type + "." + r.Name
+		b, ok := m.resources[key]
+		if !ok {
+			b = &skeletonBlock{mode: r.Mode, typ: r.Type, name: r.Name}
+			m.resources[key] = b
+		}
+		b.keys = append(b.keys, r.Index)
+	}
+
+	files := make(map[string][]byte, len(modules))
+	for key, m := range modules {
+		src, err := m.render(key)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(m.dir, "main.tf")] = src
+	}
+	return files, nil
+}
+
+// WriteConfig writes the skeleton configuration generated for addrs under
+// the directory root. It does not overwrite existing files.
+func WriteConfig(root string, addrs []*Address) error {
+	files, err := GenerateConfig(addrs)
+	if err != nil {
+		return err
+	}
+	for name := range files {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); err == nil {
+			return fmt.Errorf("%s already exists", name)
+		}
+	}
+	for name, src := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skeletonDir is the directory of the module at p, relative to the root.
+func skeletonDir(p ModulePath) string {
+	dir := "."
+	for _, step := range p {
+		dir = path.Join(dir, "modules", step.Name)
+	}
+	return dir
+}
+
+type skeletonModule struct {
+	dir       string
+	calls     map[string]*skeletonBlock
+	resources map[string]*skeletonBlock
+}
+
+type skeletonBlock struct {
+	mode ResourceMode
+	typ  string
+	name string
+	keys []Index
+}
+
+func (m *skeletonModule) render(key string) ([]byte, error) {
+	var blocks []string
+
+	names := make([]string, 0, len(m.calls))
+	for name := range m.calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		repetition, err := inferRepetition(m.calls[name].keys)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", strings.TrimPrefix(key+"."+name, "."), err)
+		}
+		attrs := append([][2]string{{"source", hclString("./" + path.Join("modules", name))}}, repetition...)
+		blocks = append(blocks, renderBlock(fmt.Sprintf("module %s", hclString(name)), attrs))
+	}
+
+	resources := make([]*skeletonBlock, 0, len(m.resources))
+	for _, r := range m.resources {
+		resources = append(resources, r)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.mode != b.mode {
+			return a.mode < b.mode
+		}
+		if a.typ != b.typ {
+			return a.typ < b.typ
+		}
+		return a.name < b.name
+	})
+	for _, r := range resources {
+		spec := ResourceSpec{Mode: r.mode, Type: r.typ, Name: r.name}
+		repetition, err := inferRepetition(r.keys)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.String(), err)
+		}
+		header := fmt.Sprintf("%s %s %s", blockTypes[r.mode], hclString(r.typ), hclString(r.name))
+		blocks = append(blocks, renderBlock(header, repetition))
+	}
+	return []byte(strings.Join(blocks, "\n")), nil
+}
+
+var blockTypes = map[ResourceMode]string{
+	ManagedResourceMode: "resource",
+	DataResourceMode:    "data",
+}
+
+// inferRepetition returns the `count` or `for_each` attribute implied by the
+// instance keys of a resource or module call.
+func inferRepetition(keys []Index) ([][2]string, error) {
+	var (
+		count   int
+		strs    = make(map[string]bool)
+		hasNone bool
+		hasInt  bool
+	)
+	for _, k := range keys {
+		switch v := k.Value.(type) {
+		case nil:
+			hasNone = true
+		case int:
+			hasInt = true
+			if v < 0 {
+				return nil, fmt.Errorf("negative index %d", v)
+			}
+			if v+1 > count {
+				count = v + 1
+			}
+		case string:
+			strs[v] = true
+		default:
+			return nil, fmt.Errorf("cannot infer repetition from index %s", k.String())
+		}
+	}
+	kinds := 0
+	for _, b := range []bool{hasNone, hasInt, len(strs) > 0} {
+		if b {
+			kinds++
+		}
+	}
+	switch {
+	case kinds > 1:
+		return nil, fmt.Errorf("instance keys are of mixed kinds")
+	case hasInt:
+		return [][2]string{{"count", fmt.Sprintf("%d", count)}}, nil
+	case len(strs) > 0:
+		set := make([]string, 0, len(strs))
+		for s := range strs {
+			set = append(set, hclString(s))
+		}
+		sort.Strings(set)
+		return [][2]string{{"for_each", fmt.Sprintf("toset([%s])", strings.Join(set, ", "))}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// renderBlock renders a block with the given attributes, aligning their
+// equals signs as `terraform fmt` does.
+func renderBlock(header string, attrs [][2]string) string {
+	if len(attrs) == 0 {
+		return header + " {}\n"
+	}
+	width := 0
+	for _, a := range attrs {
+		if len(a[0]) > width {
+			width = len(a[0])
+		}
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s {\n", header)
+	for _, a := range attrs {
+		fmt.Fprintf(&b, "  %-*s = %s\n", width, a[0], a[1])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// hclString quotes s as a string literal in Terraform's native syntax. Unlike
+// a Go string literal, template sequences must be escaped and only the
+// escapes `\n`, `\r`, `\t`, `\"`, `\\` and `\uNNNN` are available.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package address
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseAll(t *testing.T, addrs ...string) []*Address {
+	var as []*Address
+	for _, s := range addrs {
+		a, err := NewAddress(s)
+		require.NoError(t, err)
+		as = append(as, a)
+	}
+	return as
+}
+
+func TestGenerateConfig(t *testing.T) {
+	files, err := GenerateConfig(parseAll(t,
+		`aws_instance.web[0]`,
+		`aws_instance.web[2]`,
+		`data.aws_ami.ubuntu`,
+		`aws_s3_bucket.b["logs"]`,
+		`aws_s3_bucket.b["assets"]`,
+		`module.app["prod"].module.db[0].aws_db_instance.main`,
+		`module.app["dev"].module.db[0].aws_db_instance.main`,
+		`module.app["dev"].aws_iam_role.r["a${b}"]`,
+	))
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.tf", "modules/app/main.tf", "modules/app/modules/db/main.tf"}, sortedKeys(files))
+
+	require.Equal(t, `module "app" {
+  source   = "./modules/app"
+  for_each = toset(["dev", "prod"])
+}
+
+resource "aws_instance" "web" {
+  count = 3
+}
+
+resource "aws_s3_bucket" "b" {
+  for_each = toset(["assets", "logs"])
+}
+
+data "aws_ami" "ubuntu" {}
+`, string(files["main.tf"]))
+
+	require.Equal(t, `module "db" {
+  source = "./modules/db"
+  count  = 1
+}
+
+resource "aws_iam_role" "r" {
+  for_each = toset(["a$${b}"])
+}
+`, string(files["modules/app/main.tf"]))
+
+	require.Equal(t, `resource "aws_db_instance" "main" {}
+`, string(files["modules/app/modules/db/main.tf"]))
+}
+
+func TestGenerateConfigModules(t *testing.T) {
+	files, err := GenerateConfig([]*Address{
+		{ModulePath: ModulePath{{Name: "a"}, {Name: "b", Index: Index{Value: "x"}}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.tf", "modules/a/main.tf", "modules/a/modules/b/main.tf"}, sortedKeys(files))
+	require.Equal(t, `module "b" {
+  source   = "./modules/b"
+  for_each = toset(["x"])
+}
+`, string(files["modules/a/main.tf"]))
+	require.Equal(t, ``, string(files["modules/a/modules/b/main.tf"]))
+}
+
+func TestGenerateConfigErrors(t *testing.T) {
+	var tests = [][]string{
+		{`foo.bar`, `foo.bar[0]`},
+		{`foo.bar[0]`, `foo.bar["a"]`},
+		{`foo.bar[*]`},
+		{`module.a.foo.bar`, `module.a[0].foo.baz`},
+	}
+	for _, tt := range tests {
+		t.Run(tt[0], func(t *testing.T) {
+			_, err := GenerateConfig(parseAll(t, tt...))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	root := t.TempDir()
+
+	addrs := parseAll(t, `module.a.foo.bar`)
+	require.NoError(t, WriteConfig(root, addrs))
+	src, err := os.ReadFile(filepath.Join(root, "modules", "a", "main.tf"))
+	require.NoError(t, err)
+	require.Equal(t, "resource \"foo\" \"bar\" {}\n", string(src))
+
+	require.Error(t, WriteConfig(root, addrs))
+}
+
+func TestHCLString(t *testing.T) {
+	require.Equal(t, `"a\"b\\c\n\u0001%%{d}é"`, hclString("a\"b\\c\n\x01%{d}é"))
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
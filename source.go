@@ -0,0 +1,364 @@
+package address
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SourcePos is a position in a configuration file. Line and Column are
+// 1-based; Column counts characters.
+type SourcePos struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// String representation of the position, as `file:line:column`.
+func (p SourcePos) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Declaration holds the blocks that declare an address.
+type Declaration struct {
+	// Modules holds the `module` block of each step of the module path, in
+	// order.
+	Modules []SourcePos
+	// Resource is the `resource` or `data` block of the resource.
+	Resource SourcePos
+}
+
+// Locate finds the blocks that declare the address a in the configuration
+// rooted at the directory root. Module calls are followed into local module
+// directories and, when the configuration has been initialized, into the
+// modules installed by `terraform init`.
+//
+// Only the native syntax (`.tf` files) is understood.
+func Locate(root string, a *Address) (*Declaration, error) {
+	manifest, err := LoadModuleManifest(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	d := &Declaration{}
+	dir := root
+	for i, step := range a.ModulePath {
+		blocks, err := scanDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		b := findBlock(blocks, "module", step.Name)
+		if b == nil {
+			return nil, fmt.Errorf("module %q is not declared in %s", step.Name, dir)
+		}
+		d.Modules = append(d.Modules, b.pos)
+
+		if manifest != nil {
+			if r, err := manifest.Module(a.ModulePath[:i+1]); err == nil {
+				dir = r.Dir
+				continue
+			}
+		}
+		source := b.attrs["source"]
+		if !isLocalSource(source) {
+			return nil, fmt.Errorf("module %q from %q is not installed", a.ModulePath[:i+1].CallKey(), source)
+		}
+		dir = filepath.Join(dir, filepath.FromSlash(source))
+	}
+
+	blocks, err := scanDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	blockType := "resource"
+	if a.ResourceSpec.Mode == DataResourceMode {
+		blockType = "data"
+	}
+	b := findBlock(blocks, blockType, a.ResourceSpec.Type, a.ResourceSpec.Name)
+	if b == nil {
+		return nil, fmt.Errorf("%s is not declared in %s", a.ResourceSpec.String(), dir)
+	}
+	d.Resource = b.pos
+	return d, nil
+}
+
+// isLocalSource reports whether a module source address is a local path.
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// block is a top-level block of a configuration file.
+type block struct {
+	typ    string
+	labels []string
+	pos    SourcePos
+	// attrs holds the attributes of the block that are string literals.
+	attrs map[string]string
+}
+
+func findBlock(blocks []*block, typ string, labels ...string) *block {
+	for _, b := range blocks {
+		if b.typ != typ || len(b.labels) != len(labels) {
+			continue
+		}
+		match := true
+		for i, l := range labels {
+			match = match && b.labels[i] == l
+		}
+		if match {
+			return b
+		}
+	}
+	return nil
+}
+
+// scanDir scans the top-level blocks of every `.tf` file in dir, in file
+// name order.
+func scanDir(dir string) ([]*block, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var blocks []*block
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := scanBlocks(f, src)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, bs...)
+	}
+	return blocks, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+	tokenEqual
+	tokenNewline
+	tokenOther
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   SourcePos
+}
+
+// scanBlocks finds the top-level blocks of a configuration file and the
+// string literal attributes directly inside them. It understands just enough
+// of the native syntax to do so: comments, quoted strings with interpolation,
+// heredocs and nesting.
+func scanBlocks(filename string, src []byte) ([]*block, error) {
+	tokens, err := tokenize(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		blocks []*block
+		cur    *block
+		header []token
+		line   []token
+		depth  int
+	)
+	// attr records the attribute on the current line, if it is a string
+	// literal directly inside the current block.
+	attr := func(depth int) {
+		if depth == 1 && cur != nil && len(line) == 3 &&
+			line[0].kind == tokenIdent && line[1].kind == tokenEqual && line[2].kind == tokenString {
+			cur.attrs[line[0].value] = line[2].value
+		}
+	}
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenOpen:
+			if depth == 0 && len(header) > 0 && header[0].kind == tokenIdent {
+				cur = &block{typ: header[0].value, pos: header[0].pos, attrs: make(map[string]string)}
+				for _, l := range header[1:] {
+					cur.labels = append(cur.labels, l.value)
+				}
+				blocks = append(blocks, cur)
+			}
+			header, line = nil, nil
+			depth++
+		case tokenClose:
+			attr(depth)
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%s: unexpected }", t.pos)
+			}
+			header, line = nil, nil
+		case tokenNewline:
+			attr(depth)
+			header, line = nil, nil
+		default:
+			if depth == 0 {
+				header = append(header, t)
+			}
+			line = append(line, t)
+		}
+	}
+	return blocks, nil
+}
+
+func tokenize(filename string, src []byte) ([]token, error) {
+	var tokens []token
+	line, col := 1, 1
+	pos := func() SourcePos { return SourcePos{filename, line, col} }
+	// advance moves past n bytes of src, keeping track of the position.
+	advance := func(n int) {
+		for _, r := range string(src[:n]) {
+			if r == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+		}
+		src = src[n:]
+	}
+	for len(src) > 0 {
+		p := pos()
+		r, size := utf8.DecodeRune(src)
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{kind: tokenNewline, pos: p})
+			advance(size)
+		case r == ' ' || r == '\t' || r == '\r':
+			advance(size)
+		case r == '#' || bytes.HasPrefix(src, []byte("//")):
+			n := bytes.IndexByte(src, '\n')
+			if n < 0 {
+				n = len(src)
+			}
+			advance(n)
+		case bytes.HasPrefix(src, []byte("/*")):
+			n := bytes.Index(src, []byte("*/"))
+			if n < 0 {
+				return nil, fmt.Errorf("%s: unterminated comment", p)
+			}
+			advance(n + 2)
+		case bytes.HasPrefix(src, []byte("<<")):
+			n, err := heredocLen(src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			tokens = append(tokens, token{kind: tokenOther, pos: p})
+			advance(n)
+		case r == '"':
+			n, err := quotedLen(src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			v, err := strconv.Unquote(string(src[:n]))
+			if err != nil {
+				// Templates are not valid Go strings; keep them verbatim.
+				v = string(src[1 : n-1])
+			}
+			tokens = append(tokens, token{kind: tokenString, value: v, pos: p})
+			advance(n)
+		case r == '{':
+			tokens = append(tokens, token{kind: tokenOpen, pos: p})
+			advance(size)
+		case r == '}':
+			tokens = append(tokens, token{kind: tokenClose, pos: p})
+			advance(size)
+		case r == '=' && !bytes.HasPrefix(src, []byte("==")):
+			tokens = append(tokens, token{kind: tokenEqual, pos: p})
+			advance(size)
+		case isIdentRune(r, true):
+			n := size
+			for n < len(src) {
+				r, size := utf8.DecodeRune(src[n:])
+				if !isIdentRune(r, false) {
+					break
+				}
+				n += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(src[:n]), pos: p})
+			advance(n)
+		default:
+			tokens = append(tokens, token{kind: tokenOther, pos: p})
+			advance(size)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune, first bool) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+		(!first && (r == '-' || (r >= '0' && r <= '9')))
+}
+
+// quotedLen returns the length of the quoted template at the start of src,
+// including quotes. Interpolation sequences may contain nested quotes.
+func quotedLen(src []byte) (int, error) {
+	depth := 0
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '\n':
+			if depth == 0 {
+				return 0, fmt.Errorf("string literal not terminated")
+			}
+		case '$', '%':
+			// `$${` and `%%{` are escapes for a literal `${` and `%{`.
+			if i+1 < len(src) && (src[i+1] == '{' || src[i+1] == src[i]) {
+				if src[i+1] == '{' {
+					depth++
+				}
+				i++
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case '"':
+			if depth == 0 {
+				return i + 1, nil
+			}
+			n, err := quotedLen(src[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n - 1
+		}
+	}
+	return 0, fmt.Errorf("string literal not terminated")
+}
+
+// heredocLen returns the length of the heredoc at the start of src, up to
+// and excluding the newline after its closing marker.
+func heredocLen(src []byte) (int, error) {
+	s := string(src)
+	nl := strings.IndexByte(s, '\n')
+	if nl < 0 {
+		return 0, fmt.Errorf("heredoc not terminated")
+	}
+	marker := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s[:nl]), "<<"), "-")
+	for i := nl + 1; i < len(s); {
+		end := strings.IndexByte(s[i:], '\n')
+		if end < 0 {
+			end = len(s) - i
+		}
+		if strings.TrimSpace(s[i:i+end]) == marker {
+			return i + end, nil
+		}
+		i += end + 1
+	}
+	return 0, fmt.Errorf("heredoc %s not terminated", marker)
+}
@@ -0,0 +1,92 @@
+package address
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sourceFiles = map[string]string{
+	"main.tf": `# The root module.
+module "net" {
+  source = "./modules/net"
+}
+
+/* An installed module.
+resource "aws_instance" "commented" {}
+*/
+module "remote" { source = "hashicorp/consul/aws" }
+
+resource "aws_instance" "web" {
+  count     = 2
+  user_data = <<-EOF
+    resource "aws_instance" "heredoc" {
+  EOF
+  tags = { Name = "web-${lookup(var.names, "web")}" }
+}
+`,
+	"data.tf": `
+  data "aws_ami" "ubuntu" {}
+`,
+	"modules/net/main.tf": `module "inner" {
+  source = "../inner"
+}
+resource aws_subnet a {}
+`,
+	"modules/inner/main.tf": `resource "aws_route" "r" {}
+`,
+	".terraform/modules/modules.json": `{"Modules":[{"Key":"remote","Source":"registry.terraform.io/hashicorp/consul/aws","Version":"0.1.0","Dir":".terraform/modules/remote"}]}`,
+	".terraform/modules/remote/main.tf": `
+
+resource "aws_instance" "server" {}
+`,
+}
+
+func TestLocate(t *testing.T) {
+	root := t.TempDir()
+	for name, src := range sourceFiles {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+	}
+	main := filepath.Join(root, "main.tf")
+	net := filepath.Join(root, "modules", "net", "main.tf")
+
+	var tests = []struct {
+		addr     string
+		modules  []SourcePos
+		resource SourcePos
+	}{
+		{`aws_instance.web[1]`, nil, SourcePos{main, 11, 1}},
+		{`data.aws_ami.ubuntu`, nil, SourcePos{filepath.Join(root, "data.tf"), 2, 3}},
+		{`module.net.aws_subnet.a`, []SourcePos{{main, 2, 1}}, SourcePos{net, 4, 1}},
+		{`module.net.module.inner[0].aws_route.r`, []SourcePos{{main, 2, 1}, {net, 1, 1}}, SourcePos{filepath.Join(root, "modules", "inner", "main.tf"), 1, 1}},
+		{`module.remote.aws_instance.server`, []SourcePos{{main, 9, 1}}, SourcePos{filepath.Join(root, ".terraform", "modules", "remote", "main.tf"), 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			a, err := NewAddress(tt.addr)
+			require.NoError(t, err)
+			d, err := Locate(root, a)
+			require.NoError(t, err)
+			require.Equal(t, tt.modules, d.Modules)
+			require.Equal(t, tt.resource, d.Resource)
+		})
+	}
+
+	for _, addr := range []string{
+		`aws_instance.commented`,
+		`aws_instance.heredoc`,
+		`data.aws_instance.web`,
+		`module.missing.aws_instance.web`,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			a, err := NewAddress(addr)
+			require.NoError(t, err)
+			_, err = Locate(root, a)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stateV3 is the subset of version 3 of the state format, as written by
+// Terraform 0.11 and earlier, that holds resource addresses.
+type stateV3 struct {
+	Version int
+	Modules []struct {
+		// Path is the module path starting with `root`, e.g.
+		// ["root", "network"].
+		Path      []string
+		Resources map[string]struct {
+			Type string
+		}
+	}
+}
+
+// ReadStateV3 reads a state in version 3 of the state format, as written by
+// Terraform 0.11 and earlier, and returns the addresses of its resource
+// instances in the order Terraform lists them.
+//
+// Resources were keyed as `aws_instance.web.1`, where the count index is the
+// last part of the key, and `data.aws_ami.x` for data resources. A key is
+// only read as a data resource if the type recorded for the resource agrees,
+// so that a managed resource of type `data` is not mistaken for one.
+func ReadStateV3(r io.Reader) ([]*Address, error) {
+	var s stateV3
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid state: %w", err)
+	}
+	if s.Version != 3 {
+		return nil, fmt.Errorf("unsupported state version %d, expected 3", s.Version)
+	}
+	var addrs []*Address
+	for _, m := range s.Modules {
+		if len(m.Path) == 0 || m.Path[0] != "root" {
+			return nil, fmt.Errorf("invalid module path %q: must start with root", m.Path)
+		}
+		var mp ModulePath
+		for _, name := range m.Path[1:] {
+			if !isIdentifier(name) {
+				return nil, fmt.Errorf("invalid module path %q: invalid module name %q", m.Path, name)
+			}
+			mp = append(mp, Module{Name: name})
+		}
+		for key, res := range m.Resources {
+			rs, err := parseStateV3Key(key, res.Type)
+			if err != nil {
+				return nil, fmt.Errorf("module %q: %w", strings.Join(m.Path, "."), err)
+			}
+			addrs = append(addrs, &Address{ModulePath: mp, ResourceSpec: rs})
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Less(addrs[j])
+	})
+	return addrs, nil
+}
+
+// parseStateV3Key parses the key of a resource in a version 3 state. typ is
+// the type recorded for the resource, if any.
+func parseStateV3Key(key, typ string) (ResourceSpec, error) {
+	var rs ResourceSpec
+	parts := strings.Split(key, ".")
+	if parts[0] == "data" && typ != "data" {
+		rs.Mode = DataResourceMode
+		parts = parts[1:]
+	}
+	switch len(parts) {
+	case 3:
+		i, err := strconv.Atoi(parts[2])
+		if err != nil || i < 0 {
+			return ResourceSpec{}, fmt.Errorf("invalid resource key %q: invalid count index %q", key, parts[2])
+		}
+		rs.Index = Index{Value: i}
+	case 2:
+	default:
+		return ResourceSpec{}, fmt.Errorf("invalid resource key %q", key)
+	}
+	rs.Type, rs.Name = parts[0], parts[1]
+	if !isIdentifier(rs.Type) || !isIdentifier(rs.Name) {
+		return ResourceSpec{}, fmt.Errorf("invalid resource key %q", key)
+	}
+	if typ != "" && typ != rs.Type {
+		return ResourceSpec{}, fmt.Errorf("invalid resource key %q: type does not match %q", key, typ)
+	}
+	return rs, nil
+}
@@ -0,0 +1,69 @@
+package address
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const archivedStateV3 = `{
+  "version": 3,
+  "terraform_version": "0.11.14",
+  "serial": 7,
+  "modules": [
+    {
+      "path": ["root"],
+      "resources": {
+        "aws_instance.web.1": {"type": "aws_instance", "primary": {"id": "i-1"}, "provider": "provider.aws"},
+        "aws_instance.web.0": {"type": "aws_instance", "primary": {"id": "i-0"}, "provider": "provider.aws"},
+        "data.aws_ami.ubuntu": {"type": "aws_ami", "primary": {"id": "ami-1"}, "provider": "provider.aws"},
+        "data.foo.1": {"type": "data", "primary": {"id": "x"}, "provider": "provider.null"}
+      }
+    },
+    {
+      "path": ["root", "network", "subnets"],
+      "resources": {
+        "aws_subnet.private.2": {"type": "aws_subnet", "primary": {"id": "subnet-2"}},
+        "data.aws_vpc.main.0": {"type": "aws_vpc", "primary": {"id": "vpc-1"}}
+      }
+    }
+  ]
+}`
+
+func TestReadStateV3(t *testing.T) {
+	addrs, err := ReadStateV3(strings.NewReader(archivedStateV3))
+	require.NoError(t, err)
+
+	var actual []string
+	for _, a := range addrs {
+		actual = append(actual, a.String())
+	}
+	require.Equal(t, []string{
+		`aws_instance.web[0]`,
+		`aws_instance.web[1]`,
+		`data.foo[1]`,
+		`data.aws_ami.ubuntu`,
+		`module.network.module.subnets.aws_subnet.private[2]`,
+		`module.network.module.subnets.data.aws_vpc.main[0]`,
+	}, actual)
+	require.Equal(t, ManagedResourceMode, addrs[2].ResourceSpec.Mode)
+	require.Equal(t, "data", addrs[2].ResourceSpec.Type)
+}
+
+func TestReadStateV3Invalid(t *testing.T) {
+	for _, state := range []string{
+		`{"version": 4, "resources": []}`,
+		`{"version": 3, "modules": [{"path": ["network"]}]}`,
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {"aws_instance": {}}}]}`,
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {"aws_instance.web.x": {}}}]}`,
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {"aws_instance.web.-1": {}}}]}`,
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {"aws_instance.web": {"type": "aws_eip"}}}]}`,
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {"data.aws_ami": {"type": "aws_ami"}}}]}`,
+	} {
+		t.Run(state, func(t *testing.T) {
+			_, err := ReadStateV3(strings.NewReader(state))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package address
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Summary describes the complexity of a set of resource instances, such as
+// those in a state. It encodes to JSON with the field names below.
+type Summary struct {
+	// Instances is the number of resource instances. Deposed objects and
+	// module addresses are not counted.
+	Instances int `json:"instances"`
+	// Resources is the number of resources, each counting all of its
+	// instances once.
+	Resources int `json:"resources"`
+	// Modules, Types and Providers count instances by module call, as
+	// returned by ModulePath.CallKey, by resource type and by provider,
+	// as returned by ResourceSpec.Provider. The root module has an empty
+	// call key.
+	Modules   map[string]int `json:"modules"`
+	Types     map[string]int `json:"types"`
+	Providers map[string]int `json:"providers"`
+	// Repetition counts resources by how they are repeated.
+	Repetition RepetitionCounts `json:"repetition"`
+	// MaxModuleDepth is the largest number of module steps of an instance.
+	MaxModuleDepth int `json:"max_module_depth"`
+	// WidestForEach is the resource or module call with the most instances
+	// keyed by string, if any.
+	WidestForEach *Widest `json:"widest_for_each,omitempty"`
+}
+
+// RepetitionCounts counts resources by the keys of their instances: integer
+// keys for `count`, string keys for `for_each` and no key for singletons.
+// Resources whose keys are all unknown are counted as Unknown.
+type RepetitionCounts struct {
+	Count     int `json:"count"`
+	ForEach   int `json:"for_each"`
+	Singleton int `json:"singleton"`
+	Unknown   int `json:"unknown,omitempty"`
+}
+
+// Widest is a resource or module call with its number of instances.
+type Widest struct {
+	Address   string `json:"address"`
+	Instances int    `json:"instances"`
+}
+
+// Summarize summarizes addrs. See ResourceSpec.Provider for known.
+func Summarize(addrs []*Address, known ...string) *Summary {
+	s := &Summary{
+		Modules:   make(map[string]int),
+		Types:     make(map[string]int),
+		Providers: make(map[string]int),
+	}
+	// The instance keys of each resource, and the string keys of each
+	// resource or module call.
+	resources := make(map[string][]Index)
+	forEach := make(map[string]map[Index]bool)
+	addKey := func(call string, key Index) {
+		if forEach[call] == nil {
+			forEach[call] = make(map[Index]bool)
+		}
+		forEach[call][key] = true
+	}
+
+	for _, a := range addrs {
+		if a.IsModule() || a.DeposedKey != "" {
+			continue
+		}
+		s.Instances++
+		s.Modules[a.ModulePath.CallKey()]++
+		s.Types[a.ResourceSpec.Type]++
+		s.Providers[a.ResourceSpec.Provider(known...)]++
+		if len(a.ModulePath) > s.MaxModuleDepth {
+			s.MaxModuleDepth = len(a.ModulePath)
+		}
+
+		r := a.Clone()
+		r.ResourceSpec.Index = Index{}
+		resources[r.String()] = append(resources[r.String()], a.ResourceSpec.Index)
+		if _, ok := a.ResourceSpec.Index.Value.(string); ok {
+			addKey(r.String(), a.ResourceSpec.Index)
+		}
+		for i, m := range a.ModulePath {
+			if _, ok := m.Index.Value.(string); ok {
+				call := append(ModulePath{}, a.ModulePath[:i+1]...)
+				call[i].Index = Index{}
+				addKey(call.String(), m.Index)
+			}
+		}
+	}
+
+	s.Resources = len(resources)
+	for _, keys := range resources {
+		// Classify by the first known key, since unknown keys may stand
+		// for keys of any kind.
+		key := keys[0]
+		for _, k := range keys {
+			if !k.IsUnknown() {
+				key = k
+				break
+			}
+		}
+		switch key.Value.(type) {
+		case int:
+			s.Repetition.Count++
+		case string:
+			s.Repetition.ForEach++
+		case nil:
+			s.Repetition.Singleton++
+		default:
+			s.Repetition.Unknown++
+		}
+	}
+
+	for call, keys := range forEach {
+		if s.WidestForEach == nil || len(keys) > s.WidestForEach.Instances ||
+			(len(keys) == s.WidestForEach.Instances && call < s.WidestForEach.Address) {
+			s.WidestForEach = &Widest{Address: call, Instances: len(keys)}
+		}
+	}
+	return s
+}
+
+// WriteTable writes the summary as a text table, with each count sorted from
+// the largest.
+func (s *Summary) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Instances\t%d\n", s.Instances)
+	fmt.Fprintf(tw, "Resources\t%d\n", s.Resources)
+	fmt.Fprintf(tw, "Max module depth\t%d\n", s.MaxModuleDepth)
+	if s.WidestForEach != nil {
+		fmt.Fprintf(tw, "Widest for_each\t%s (%d instances)\n", s.WidestForEach.Address, s.WidestForEach.Instances)
+	}
+
+	fmt.Fprintf(tw, "\nREPETITION\tRESOURCES\n")
+	fmt.Fprintf(tw, "count\t%d\n", s.Repetition.Count)
+	fmt.Fprintf(tw, "for_each\t%d\n", s.Repetition.ForEach)
+	fmt.Fprintf(tw, "singleton\t%d\n", s.Repetition.Singleton)
+	if s.Repetition.Unknown > 0 {
+		fmt.Fprintf(tw, "unknown\t%d\n", s.Repetition.Unknown)
+	}
+
+	for _, t := range []struct {
+		heading string
+		counts  map[string]int
+	}{
+		{"MODULE", s.Modules},
+		{"PROVIDER", s.Providers},
+		{"TYPE", s.Types},
+	} {
+		fmt.Fprintf(tw, "\n%s\tINSTANCES\n", t.heading)
+		for _, k := range sortedCounts(t.counts) {
+			name := k
+			if t.heading == "MODULE" && name == "" {
+				name = "(root)"
+			}
+			fmt.Fprintf(tw, "%s\t%d\n", name, t.counts[k])
+		}
+	}
+	return tw.Flush()
+}
+
+// sortedCounts returns the keys of counts from the largest count, then by
+// key.
+func sortedCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,109 @@
+package address
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	addrs := parseAll(t,
+		`aws_instance.web[0]`,
+		`aws_instance.web[1]`,
+		`aws_instance.web[1] (deposed object 00000001)`,
+		`data.aws_ami.ubuntu`,
+		`module.app["a"].aws_s3_bucket.logs`,
+		`module.app["b"].aws_s3_bucket.logs`,
+		`module.app["c"].aws_s3_bucket.logs`,
+		`module.app["a"].module.db.google_sql_database_instance.x["eu"]`,
+		`module.app["a"].module.db.google_sql_database_instance.x["us"]`,
+		`module.net[*].aws_vpc.main`,
+		`module.net[0].aws_subnet.x[*]`,
+	)
+	addrs = append(addrs, &Address{ModulePath: ModulePath{{Name: "app"}}})
+
+	s := Summarize(addrs)
+	require.Equal(t, &Summary{
+		Instances: 10,
+		Resources: 8,
+		Modules:   map[string]int{"": 3, "app": 3, "app.db": 2, "net": 2},
+		Types: map[string]int{
+			"aws_instance":                 2,
+			"aws_ami":                      1,
+			"aws_s3_bucket":                3,
+			"google_sql_database_instance": 2,
+			"aws_vpc":                      1,
+			"aws_subnet":                   1,
+		},
+		Providers:      map[string]int{"aws": 8, "google": 2},
+		Repetition:     RepetitionCounts{Count: 1, ForEach: 1, Singleton: 5, Unknown: 1},
+		MaxModuleDepth: 2,
+		WidestForEach:  &Widest{Address: "module.app", Instances: 3},
+	}, s)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"instances": 10,
+		"resources": 8,
+		"modules": {"": 3, "app": 3, "app.db": 2, "net": 2},
+		"types": {
+			"aws_instance": 2,
+			"aws_ami": 1,
+			"aws_s3_bucket": 3,
+			"google_sql_database_instance": 2,
+			"aws_vpc": 1,
+			"aws_subnet": 1
+		},
+		"providers": {"aws": 8, "google": 2},
+		"repetition": {"count": 1, "for_each": 1, "singleton": 5, "unknown": 1},
+		"max_module_depth": 2,
+		"widest_for_each": {"address": "module.app", "instances": 3}
+	}`, string(b))
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteTable(&buf))
+	require.Equal(t, `Instances         10
+Resources         8
+Max module depth  2
+Widest for_each   module.app (3 instances)
+
+REPETITION  RESOURCES
+count       1
+for_each    1
+singleton   5
+unknown     1
+
+MODULE  INSTANCES
+(root)  3
+app     3
+app.db  2
+net     2
+
+PROVIDER  INSTANCES
+aws       8
+google    2
+
+TYPE                          INSTANCES
+aws_s3_bucket                 3
+aws_instance                  2
+google_sql_database_instance  2
+aws_ami                       1
+aws_subnet                    1
+aws_vpc                       1
+`, buf.String())
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	s := Summarize(nil)
+	require.Zero(t, s.Instances)
+	require.Nil(t, s.WidestForEach)
+	require.Empty(t, s.Modules)
+}
+
+func TestSummarizeUnknownKeys(t *testing.T) {
+	s := Summarize(parseAll(t, `foo.bar[*]`, `foo.bar["a"]`, `foo.bar["b"]`, `foo.baz[*]`))
+	require.Equal(t, RepetitionCounts{ForEach: 1, Unknown: 1}, s.Repetition)
+}
@@ -0,0 +1,48 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// SyntaxError describes where parsing an address failed, for editors and
+// forms that highlight the error.
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the address. Line and
+	// Column are 1-based, with Column counted in characters.
+	Offset int
+	Line   int
+	Column int
+	// Expected lists what the parser expected at Offset, if known.
+	Expected []string
+	// Message describes the error, without its position.
+	Message string
+}
+
+// SyntaxErrors returns the positions of the errors returned by NewAddress,
+// in the order they were found. Returns nil if err is not a parse error.
+func SyntaxErrors(err error) []SyntaxError {
+	var list errList
+	if !errors.As(err, &list) {
+		return nil
+	}
+	var errs []SyntaxError
+	for _, e := range list {
+		var pe *parserError
+		if !errors.As(e, &pe) {
+			continue
+		}
+		se := SyntaxError{
+			Offset:  pe.pos.offset,
+			Line:    pe.pos.line,
+			Column:  pe.pos.col,
+			Message: pe.Inner.Error(),
+		}
+		if len(pe.expected) > 0 {
+			se.Expected = pe.expected
+			se.Message, _, _ = strings.Cut(se.Message, ", expected:")
+		}
+		errs = append(errs, se)
+	}
+	return errs
+}
@@ -0,0 +1,38 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntaxErrors(t *testing.T) {
+	var tests = []struct {
+		addr     string
+		expected []SyntaxError
+	}{
+		{
+			`foo`,
+			[]SyntaxError{{Offset: 3, Line: 1, Column: 4, Expected: []string{`"."`, `[a-zA-Z0-9_-]i`}, Message: "no match found"}},
+		},
+		{
+			`module.é.foo.bar`,
+			[]SyntaxError{{Offset: 7, Line: 1, Column: 8, Expected: []string{`[a-z_-]i`}, Message: "no match found"}},
+		},
+		{
+			`foo.bar[99999999999999999999]`,
+			[]SyntaxError{{Offset: 8, Line: 1, Column: 9, Message: `strconv.Atoi: parsing "99999999999999999999": value out of range`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			_, err := NewAddress(tt.addr)
+			require.Error(t, err)
+			require.Equal(t, tt.expected, SyntaxErrors(err))
+		})
+	}
+
+	require.Nil(t, SyntaxErrors(errors.New("foo")))
+	require.Nil(t, SyntaxErrors(nil))
+}
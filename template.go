@@ -0,0 +1,115 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FuncMap returns functions for manipulating addresses in templates. It can
+// be passed to the Funcs method of both text/template and html/template.
+// Functions taking an address accept either an *Address or a string to
+// parse.
+//
+//	addrParse "foo.bar"        parses an address
+//	addrModule ADDR            the module path, e.g. `module.a[0]`
+//	addrParent ADDR            the address containing ADDR, see Parent
+//	addrType ADDR              the resource type
+//	addrName ADDR              the resource name
+//	addrKey ADDR               the instance key, unquoted; empty if absent
+//	addrShellQuote ADDR        the address quoted for a POSIX shell
+//	addrHCL ADDR               the address as an HCL traversal, for `moved`
+//	                           and `import` blocks
+//	addrWithKey KEY ADDR       ADDR with its resource instance key set to KEY;
+//	                           an error for a module address, which has no
+//	                           resource key
+func FuncMap() map[string]any {
+	return map[string]any{
+		"addrParse": NewAddress,
+		"addrModule": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			return a.ModulePath.String(), nil
+		},
+		"addrParent": func(v any) (*Address, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return nil, err
+			}
+			return a.Parent(), nil
+		},
+		"addrType": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			return a.ResourceSpec.Type, nil
+		},
+		"addrName": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			return a.ResourceSpec.Name, nil
+		},
+		"addrKey": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			if s, ok := a.ResourceSpec.Index.Value.(string); ok {
+				return s, nil
+			}
+			return a.ResourceSpec.Index.String(), nil
+		},
+		"addrShellQuote": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			return "'" + strings.ReplaceAll(a.String(), "'", `'\''`) + "'", nil
+		},
+		"addrHCL": func(v any) (string, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return "", err
+			}
+			return a.HCL(), nil
+		},
+		"addrWithKey": func(key any, v any) (*Address, error) {
+			a, err := templateAddress(v)
+			if err != nil {
+				return nil, err
+			}
+			if a.IsModule() {
+				return nil, fmt.Errorf("cannot set the instance key of module address %s", a)
+			}
+			b := a.Clone()
+			switch k := key.(type) {
+			case int:
+				b.ResourceSpec.Index = Index{Value: k}
+			case int64:
+				b.ResourceSpec.Index = Index{Value: int(k)}
+			case string:
+				b.ResourceSpec.Index = Index{Value: k}
+			case nil:
+				b.ResourceSpec.Index = Index{}
+			default:
+				return nil, fmt.Errorf("invalid instance key %v of type %T", key, key)
+			}
+			return b, nil
+		},
+	}
+}
+
+func templateAddress(v any) (*Address, error) {
+	switch a := v.(type) {
+	case *Address:
+		return a, nil
+	case string:
+		return NewAddress(a)
+	default:
+		return nil, fmt.Errorf("expected an address, got %T", v)
+	}
+}
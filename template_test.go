@@ -0,0 +1,66 @@
+package address
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncMap(t *testing.T) {
+	var tests = []struct {
+		tpl      string
+		expected string
+	}{
+		{`{{ addrParse "module.a.foo.bar[0]" }}`, `module.a.foo.bar[0]`},
+		{`{{ addrModule . }}`, `module.a["x"]`},
+		{`{{ addrParent . }}`, `module.a["x"].aws_s3_bucket.b`},
+		{`{{ addrParent . | addrParent }}`, `module.a["x"]`},
+		{`{{ addrType . }}.{{ addrName . }}`, `aws_s3_bucket.b`},
+		{`{{ addrKey . }}`, `it's ${x}`},
+		{`{{ addrKey "foo.bar[3]" }}`, `3`},
+		{`{{ addrKey "foo.bar" }}`, ``},
+		{`terraform state show {{ addrShellQuote . }}`, `terraform state show 'module.a["x"].aws_s3_bucket.b["it'\''s ${x}"]'`},
+		{`to = {{ addrHCL . }}`, `to = module.a["x"].aws_s3_bucket.b["it's $${x}"]`},
+		{`{{ addrWithKey 2 . }}`, `module.a["x"].aws_s3_bucket.b[2]`},
+		{`{{ . | addrWithKey "y" | addrHCL }}`, `module.a["x"].aws_s3_bucket.b["y"]`},
+	}
+	a := parseAll(t, `module.a["x"].aws_s3_bucket.b["it's ${x}"]`)[0]
+	for _, tt := range tests {
+		t.Run(tt.tpl, func(t *testing.T) {
+			tpl, err := template.New("").Funcs(FuncMap()).Parse(tt.tpl)
+			require.NoError(t, err)
+			var b strings.Builder
+			require.NoError(t, tpl.Execute(&b, a))
+			require.Equal(t, tt.expected, b.String())
+		})
+	}
+
+	tpl := template.Must(template.New("").Funcs(FuncMap()).Parse(`{{ addrModule "foo" }}`))
+	require.Error(t, tpl.Execute(&strings.Builder{}, nil))
+
+	tpl = template.Must(template.New("").Funcs(FuncMap()).Parse(`{{ addrWithKey 2 (addrParent "module.a.foo.bar") }}`))
+	err := tpl.Execute(&strings.Builder{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot set the instance key of module address module.a")
+}
+
+func TestFuncMapHTML(t *testing.T) {
+	tpl, err := htmltemplate.New("").Funcs(FuncMap()).Parse(`<code>{{ addrHCL . }}</code>`)
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, tpl.Execute(&b, parseAll(t, `foo.bar["<x>"]`)[0]))
+	require.Equal(t, `<code>foo.bar[&#34;&lt;x&gt;&#34;]</code>`, b.String())
+}
+
+func TestParent(t *testing.T) {
+	a := parseAll(t, `module.a[0].foo.bar[1] (deposed object 1a2b3c4d)`)[0]
+	var chain []string
+	for p := a.Parent(); p != nil; p = p.Parent() {
+		require.True(t, p.Contains(a), "%s contains %s", p, a)
+		chain = append(chain, p.String())
+	}
+	require.Equal(t, []string{`module.a[0].foo.bar[1]`, `module.a[0].foo.bar`, `module.a[0]`, `module.a`, ``}, chain)
+}
@@ -0,0 +1,89 @@
+package address
+
+import "fmt"
+
+// RunReference is a reference to the output of a run block in a Terraform
+// test file, such as `run.setup.vpc_id` or `run.setup.subnets[0].id`.
+type RunReference struct {
+	// Run is the name of the run block.
+	Run string
+	// Output is the name of the output of the run block's module.
+	Output string
+	// Traversal is the rest of the reference after the output, such as
+	// `[0].id`. It is not validated.
+	Traversal string
+}
+
+// ParseRunReference parses a reference to a run block output. Returns an
+// error if the reference is malformed.
+func ParseRunReference(s string) (*RunReference, error) {
+	r, err := Parse(s, []byte(s), Entrypoint("RunReference"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid run reference %q: %w", s, err)
+	}
+	return r.(*RunReference), nil
+}
+
+// String representation of the reference.
+func (r *RunReference) String() string {
+	return "run." + r.Run + "." + r.Output + r.Traversal
+}
+
+// ParseModuleAddress parses the address of a module instance, such as
+// `module.a[0].module.b`, into an Address with an empty ResourceSpec.
+func ParseModuleAddress(s string) (*Address, error) {
+	a, err := Parse(s, []byte(s), Entrypoint("ModuleAddress"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid module address %q: %w", s, err)
+	}
+	return a.(*Address), nil
+}
+
+// ParseOverrideTarget parses and validates the target of an override block
+// in a Terraform test file: a managed resource for `override_resource`, a
+// data resource for `override_data`, and a module for `override_module`.
+// Resource targets may select a single instance, but not a deposed object.
+func ParseOverrideTarget(block, target string) (*Address, error) {
+	var (
+		a    *Address
+		err  error
+		mode ResourceMode
+	)
+	switch block {
+	case "override_module":
+		if a, err = ParseModuleAddress(target); err != nil {
+			return nil, fmt.Errorf("invalid %s target %q: %w", block, target, err)
+		}
+	case "override_resource":
+		mode = ManagedResourceMode
+	case "override_data":
+		mode = DataResourceMode
+	default:
+		return nil, fmt.Errorf("unknown override block %q", block)
+	}
+	if a == nil {
+		if a, err = NewAddress(target); err != nil {
+			return nil, fmt.Errorf("invalid %s target %q: %w", block, target, err)
+		}
+	}
+	switch {
+	case a.IsModule():
+		// Module targets have no resource to check.
+	case a.ResourceSpec.Mode == ManagedResourceMode && a.ResourceSpec.Type == "module":
+		// The grammar reads `module.child` as a resource of type module,
+		// which Terraform reserves.
+		return nil, fmt.Errorf("invalid %s target %q: must be a resource, not a module", block, target)
+	case a.DeposedKey != "":
+		return nil, fmt.Errorf("invalid %s target %q: cannot target a deposed object", block, target)
+	case a.ResourceSpec.Mode != mode:
+		return nil, fmt.Errorf("invalid %s target %q: must be a %s resource", block, target, mode)
+	case a.ResourceSpec.Index.IsUnknown():
+		return nil, fmt.Errorf("invalid %s target %q: instance key must be known", block, target)
+	}
+	for _, m := range a.ModulePath {
+		if m.Index.IsUnknown() {
+			return nil, fmt.Errorf("invalid %s target %q: instance key must be known", block, target)
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,87 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRunReference(t *testing.T) {
+	var tests = []struct {
+		ref      string
+		expected *RunReference
+	}{
+		{`run.setup.vpc_id`, &RunReference{Run: "setup", Output: "vpc_id"}},
+		{`run.setup.subnets[0].id`, &RunReference{Run: "setup", Output: "subnets", Traversal: "[0].id"}},
+		{`run.create-bucket.bucket.arn`, &RunReference{Run: "create-bucket", Output: "bucket", Traversal: ".arn"}},
+		{`run.-setup.x`, &RunReference{Run: "-setup", Output: "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			r, err := ParseRunReference(tt.ref)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, r)
+			require.Equal(t, tt.ref, r.String())
+		})
+	}
+
+	for _, ref := range []string{`var.x`, `run.`, `run.setup`, `run.setup.`, `run.0setup.x`, `run.setup.0x`, `run.setup.x y`} {
+		t.Run(ref, func(t *testing.T) {
+			_, err := ParseRunReference(ref)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseModuleAddress(t *testing.T) {
+	a, err := ParseModuleAddress(`module.a[0].module.b["x"]`)
+	require.NoError(t, err)
+	require.Equal(t, &Address{ModulePath: ModulePath{{Name: "a", Index: Index{Value: 0}}, {Name: "b", Index: Index{Value: "x"}}}}, a)
+
+	// Module names follow the grammar's identifiers, as in NewAddress.
+	a, err = ParseModuleAddress(`module.-a[*]`)
+	require.NoError(t, err)
+	require.Equal(t, &Address{ModulePath: ModulePath{{Name: "-a", Index: Index{Value: UnknownKey{}}}}}, a)
+
+	for _, s := range []string{``, `module`, `module.a.foo.bar`, `module.a.`, `foo.bar`} {
+		_, err := ParseModuleAddress(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseOverrideTarget(t *testing.T) {
+	var tests = []struct {
+		block  string
+		target string
+		err    string
+	}{
+		{"override_resource", `aws_instance.x`, ""},
+		{"override_resource", `module.child.aws_instance.x[1]`, ""},
+		{"override_data", `data.aws_ami.ubuntu`, ""},
+		{"override_module", `module.child`, ""},
+		{"override_module", `module.child["a"].module.grandchild`, ""},
+		{"override_resource", `data.aws_ami.ubuntu`, `invalid override_resource target "data.aws_ami.ubuntu": must be a managed resource`},
+		{"override_data", `aws_instance.x`, `invalid override_data target "aws_instance.x": must be a data resource`},
+		{"override_resource", `module.child`, `invalid override_resource target "module.child": must be a resource, not a module`},
+		{"override_resource", `aws_instance.x[*]`, `invalid override_resource target "aws_instance.x[*]": instance key must be known`},
+		{"override_resource", `module.a[*].aws_instance.x`, `invalid override_resource target "module.a[*].aws_instance.x": instance key must be known`},
+		{"override_module", `module.child[*]`, `invalid override_module target "module.child[*]": instance key must be known`},
+		{"override_resource", `aws_instance.x (deposed object 00000001)`, `invalid override_resource target "aws_instance.x (deposed object 00000001)": cannot target a deposed object`},
+		{"override_provider", `aws_instance.x`, `unknown override block "override_provider"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.block+" "+tt.target, func(t *testing.T) {
+			a, err := ParseOverrideTarget(tt.block, tt.target)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.target, a.String())
+		})
+	}
+
+	_, err := ParseOverrideTarget("override_module", `module.child.aws_instance.x`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid override_module target "module.child.aws_instance.x": `)
+}
@@ -0,0 +1,195 @@
+package address
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ApplyState is the progress of a single resource instance during an apply.
+type ApplyState int
+
+const (
+	// ApplyPending instances have a planned change that has not started.
+	ApplyPending ApplyState = iota
+	// ApplyInProgress instances have started applying.
+	ApplyInProgress
+	// ApplyDone instances have been applied successfully.
+	ApplyDone
+	// ApplyErrored instances failed to apply.
+	ApplyErrored
+)
+
+// String representation of the state.
+func (s ApplyState) String() string {
+	switch s {
+	case ApplyPending:
+		return "pending"
+	case ApplyInProgress:
+		return "in progress"
+	case ApplyDone:
+		return "done"
+	case ApplyErrored:
+		return "errored"
+	default:
+		return "unknown"
+	}
+}
+
+// ApplyStatus is the progress of a single resource instance.
+type ApplyStatus struct {
+	Address *Address
+	State   ApplyState
+	Action  string
+	// Started is the time of the apply_start event. Zero while pending.
+	Started time.Time
+	// Elapsed is the last elapsed time reported by Terraform.
+	Elapsed time.Duration
+}
+
+// ApplyProgress counts the resource instances of a module subtree in each
+// state.
+type ApplyProgress struct {
+	Pending    int
+	InProgress int
+	Done       int
+	Errored    int
+}
+
+// Complete reports whether no instance is pending or in progress.
+func (p ApplyProgress) Complete() bool {
+	return p.Pending == 0 && p.InProgress == 0
+}
+
+// ApplyTracker maintains the progress of every resource instance seen in a
+// stream of UI events. It is safe to query while events are being observed
+// from another goroutine.
+type ApplyTracker struct {
+	mu     sync.RWMutex
+	status map[string]*ApplyStatus
+}
+
+// NewApplyTracker returns an empty tracker.
+func NewApplyTracker() *ApplyTracker {
+	return &ApplyTracker{status: make(map[string]*ApplyStatus)}
+}
+
+// Run observes every event from d until the end of the stream. It returns
+// nil at the end of the stream, or the first decoding error.
+func (t *ApplyTracker) Run(d *EventDecoder) error {
+	for {
+		e, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		t.Observe(e)
+	}
+}
+
+// Observe updates the tracker with an event. Events that do not concern the
+// apply, such as refreshes, are ignored.
+func (t *ApplyTracker) Observe(e *Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := e.Address.String()
+	s, ok := t.status[key]
+	if !ok {
+		switch e.Type {
+		case EventPlannedChange, EventApplyStart, EventApplyProgress, EventApplyComplete, EventApplyErrored:
+		default:
+			return
+		}
+		s = &ApplyStatus{Address: e.Address.Clone(), Action: e.Action}
+		t.status[key] = s
+	}
+	switch e.Type {
+	case EventApplyStart:
+		s.State = ApplyInProgress
+		s.Action = e.Action
+		s.Started = e.Timestamp
+	case EventApplyProgress:
+		s.State = ApplyInProgress
+		s.Elapsed = e.Elapsed
+	case EventApplyComplete:
+		s.State = ApplyDone
+		s.Elapsed = e.Elapsed
+	case EventApplyErrored:
+		s.State = ApplyErrored
+		s.Elapsed = e.Elapsed
+	}
+}
+
+// Status returns the progress of the resource instance at a. The address
+// of the returned status is a copy and may be modified.
+func (t *ApplyTracker) Status(a *Address) (ApplyStatus, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.status[a.String()]
+	if !ok {
+		return ApplyStatus{}, false
+	}
+	status := *s
+	status.Address = s.Address.Clone()
+	return status, true
+}
+
+// Progress rolls up the progress of every resource instance within the
+// module subtree at m, as selected by ModulePath.Contains. An empty path
+// selects the whole configuration.
+func (t *ApplyTracker) Progress(m ModulePath) ApplyProgress {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var p ApplyProgress
+	for _, s := range t.status {
+		if !m.Contains(s.Address.ModulePath) {
+			continue
+		}
+		switch s.State {
+		case ApplyPending:
+			p.Pending++
+		case ApplyInProgress:
+			p.InProgress++
+		case ApplyDone:
+			p.Done++
+		case ApplyErrored:
+			p.Errored++
+		}
+	}
+	return p
+}
+
+// Applying returns the module instances that still have resource instances
+// pending or in progress, including their ancestors, in the order of
+// Address.Less.
+func (t *ApplyTracker) Applying() []ModulePath {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(map[string]ModulePath)
+	for _, s := range t.status {
+		if s.State != ApplyPending && s.State != ApplyInProgress {
+			continue
+		}
+		mp := s.Address.ModulePath
+		for i := 1; i <= len(mp); i++ {
+			if _, ok := seen[mp[:i].String()]; !ok {
+				seen[mp[:i].String()] = append(ModulePath(nil), mp[:i]...)
+			}
+		}
+	}
+	paths := make([]ModulePath, 0, len(seen))
+	for _, p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return (&Address{ModulePath: paths[i]}).Less(&Address{ModulePath: paths[j]})
+	})
+	return paths
+}
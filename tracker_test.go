@@ -0,0 +1,110 @@
+package address
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func trackerEvent(t *testing.T, typ EventType, addr string) *Event {
+	a, err := NewAddress(addr)
+	require.NoError(t, err)
+	return &Event{Type: typ, Address: a, Action: "create"}
+}
+
+func TestApplyTracker(t *testing.T) {
+	tr := NewApplyTracker()
+	for _, e := range []*Event{
+		trackerEvent(t, EventPlannedChange, `module.a[0].foo.bar`),
+		trackerEvent(t, EventPlannedChange, `module.a[1].module.b.foo.bar`),
+		trackerEvent(t, EventPlannedChange, `module.c.foo.bar`),
+		trackerEvent(t, EventPlannedChange, `foo.bar`),
+		trackerEvent(t, EventApplyStart, `module.a[0].foo.bar`),
+		trackerEvent(t, EventApplyStart, `module.c.foo.bar`),
+		trackerEvent(t, EventApplyErrored, `module.c.foo.bar`),
+		trackerEvent(t, EventApplyStart, `foo.bar`),
+		trackerEvent(t, EventApplyComplete, `foo.bar`),
+		trackerEvent(t, EventRefreshStart, `foo.baz`),
+	} {
+		tr.Observe(e)
+	}
+
+	a, err := NewAddress(`module.a[0].foo.bar`)
+	require.NoError(t, err)
+	s, ok := tr.Status(a)
+	require.True(t, ok)
+	require.Equal(t, ApplyInProgress, s.State)
+
+	b, err := NewAddress(`foo.baz`)
+	require.NoError(t, err)
+	_, ok = tr.Status(b)
+	require.False(t, ok)
+
+	require.Equal(t, ApplyProgress{Pending: 1, InProgress: 1, Done: 1, Errored: 1}, tr.Progress(nil))
+	require.Equal(t, ApplyProgress{Pending: 1, InProgress: 1}, tr.Progress(ModulePath{{Name: "a"}}))
+	require.Equal(t, ApplyProgress{Pending: 1}, tr.Progress(ModulePath{{Name: "a", Index: Index{1}}}))
+	require.True(t, tr.Progress(ModulePath{{Name: "c"}}).Complete())
+
+	var applying []string
+	for _, m := range tr.Applying() {
+		applying = append(applying, m.String())
+	}
+	require.Equal(t, []string{"module.a[0]", "module.a[1]", "module.a[1].module.b"}, applying)
+}
+
+func TestApplyTrackerRun(t *testing.T) {
+	tr := NewApplyTracker()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			tr.Progress(nil)
+			tr.Applying()
+		}
+	}()
+	require.NoError(t, tr.Run(NewEventDecoder(strings.NewReader(applyStream))))
+	wg.Wait()
+
+	a, err := NewAddress(`aws_instance.c[1]`)
+	require.NoError(t, err)
+	s, ok := tr.Status(a)
+	require.True(t, ok)
+	require.Equal(t, ApplyDone, s.State)
+	require.Equal(t, 3*time.Second, s.Elapsed)
+	require.Equal(t, ApplyProgress{Pending: 1, Done: 1}, tr.Progress(nil))
+}
+
+func TestApplyTrackerApplyingOrder(t *testing.T) {
+	tr := NewApplyTracker()
+	for _, addr := range []string{`module.a[10].foo.bar`, `module.a[2].foo.bar`, `module.a[2].module.b.foo.bar`, `module.a["x"].foo.bar`} {
+		tr.Observe(trackerEvent(t, EventPlannedChange, addr))
+	}
+	var applying []string
+	for _, m := range tr.Applying() {
+		applying = append(applying, m.String())
+	}
+	require.Equal(t, []string{`module.a[2]`, `module.a[2].module.b`, `module.a[10]`, `module.a["x"]`}, applying)
+}
+
+func TestApplyTrackerCopiesAddresses(t *testing.T) {
+	tr := NewApplyTracker()
+	e := trackerEvent(t, EventApplyStart, `module.a.foo.bar`)
+	tr.Observe(e)
+	e.Address.ModulePath[0].Name = "b"
+
+	a, err := NewAddress(`module.a.foo.bar`)
+	require.NoError(t, err)
+	s, ok := tr.Status(a)
+	require.True(t, ok)
+	require.Equal(t, `module.a.foo.bar`, s.Address.String())
+
+	s.Address.ModulePath[0].Name = "c"
+	s, ok = tr.Status(a)
+	require.True(t, ok)
+	require.Equal(t, `module.a.foo.bar`, s.Address.String())
+	require.Equal(t, ApplyProgress{InProgress: 1}, tr.Progress(ModulePath{{Name: "a"}}))
+}